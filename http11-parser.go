@@ -1,6 +1,10 @@
 package http11p
 
 import (
+	"bytes"
+	"net"
+	"strconv"
+
 	abnfp "github.com/um7a/abnf-parser"
 	urip "github.com/um7a/uri-parser"
 )
@@ -10,8 +14,18 @@ import (
 //  uri-host      = <host, see [URI], Section 3.2.2>
 //
 
+// NOTE
+// urip.NewHostFinder does not find some IPv6 addresses, e.g.
+// "[2001:db8::1]", so an IPv6 address in brackets is tried first.
 func NewUriHostFinder() abnfp.Finder {
-	return urip.NewHostFinder()
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('['),
+			newIpV6AddressFinder(),
+			abnfp.NewByteFinder(']'),
+		}),
+		urip.NewHostFinder(),
+	})
 }
 
 // RFC9110 - 4.1. URI References
@@ -92,6 +106,61 @@ func NewObsTextFinder() abnfp.Finder {
 	return abnfp.NewValueRangeAlternativesFinder(0x80, 0xff)
 }
 
+// RFC9110 - 5.6.1. Lists (#rule ABNF Extension)
+//
+// A recipient MUST accept lists that satisfy the following syntax:
+//
+//  #element => [ element ] *( OWS "," OWS [ element ] )
+//
+
+// NOTE
+// The recipient syntax is used so that empty list elements are tolerated,
+// e.g. "foo,,bar" and ", foo".
+func NewListFinder(element abnfp.Finder) abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(element.Copy()),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				NewOwsFinder(),
+				abnfp.NewByteFinder(','),
+				NewOwsFinder(),
+				abnfp.NewOptionalSequenceFinder(element.Copy()),
+			}),
+		),
+	})
+}
+
+// RFC9110 - 5.6.1. Lists (#rule ABNF Extension)
+//
+// A recipient MUST accept lists that satisfy the following syntax:
+//
+//  1#element => *( "," OWS ) element *( OWS "," [ OWS element ] )
+//
+
+func NewNonEmptyListFinder(element abnfp.Finder) abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(','),
+				NewOwsFinder(),
+			}),
+		),
+		element.Copy(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				NewOwsFinder(),
+				abnfp.NewByteFinder(','),
+				abnfp.NewOptionalSequenceFinder(
+					abnfp.NewConcatenationFinder([]abnfp.Finder{
+						NewOwsFinder(),
+						element.Copy(),
+					}),
+				),
+			}),
+		),
+	})
+}
+
 // RFC9110 - 5.6.2. Tokens
 //
 // token          = 1*tchar
@@ -227,6 +296,273 @@ func NewQuotedPairFinder() abnfp.Finder {
 	})
 }
 
+// RFC9110 - 5.6.5. Comments
+//
+//  comment        = "(" *( ctext / quoted-pair / comment ) ")"
+//
+
+// NOTE
+// comment is recursive, so the Finder of a nested comment is created when
+// it is used.
+func NewCommentFinder() abnfp.Finder {
+	return &commentFinder{}
+}
+
+type commentFinder struct{}
+
+func (finder *commentFinder) Find(data []byte) (found bool, end int) {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('('),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewAlternativesFinder([]abnfp.Finder{
+				NewCTextFinder(),
+				NewQuotedPairFinder(),
+				NewCommentFinder(),
+			}),
+		),
+		abnfp.NewByteFinder(')'),
+	}).Find(data)
+}
+
+func (finder *commentFinder) Copy() abnfp.Finder {
+	return &commentFinder{}
+}
+
+// RFC5234 - 2.3. Terminal Values
+//
+// ABNF strings are case insensitive and the character set for these strings
+// is US-ASCII.
+//
+
+// caseInsensitiveStringFinder finds a string of ABNF, e.g. "unknown".
+//
+// NOTE
+// abnfp.NewBytesFinder compares the bytes case-sensitively.
+type caseInsensitiveStringFinder struct {
+	target []byte
+}
+
+func newCaseInsensitiveStringFinder(target []byte) abnfp.Finder {
+	return &caseInsensitiveStringFinder{target: target}
+}
+
+func (finder *caseInsensitiveStringFinder) Find(data []byte) (found bool, end int) {
+	if len(data) < len(finder.target) || !bytes.EqualFold(data[:len(finder.target)], finder.target) {
+		return false, 0
+	}
+	return true, len(finder.target)
+}
+
+func (finder *caseInsensitiveStringFinder) Copy() abnfp.Finder {
+	return &caseInsensitiveStringFinder{target: finder.target}
+}
+
+// possessiveFinder finds what its finder finds, but is not backtracked into
+// by the Finder containing it.
+//
+// NOTE
+// abnfp.ConcatenationFinder panics when it backtracks into a nested
+// ConcatenationFinder whose last element has a variable length, e.g.
+// received-protocol in via-element.
+type possessiveFinder struct {
+	finder abnfp.Finder
+}
+
+func newPossessiveFinder(finder abnfp.Finder) abnfp.Finder {
+	return &possessiveFinder{finder: finder}
+}
+
+func (finder *possessiveFinder) Find(data []byte) (found bool, end int) {
+	return finder.finder.Copy().Find(data)
+}
+
+func (finder *possessiveFinder) Copy() abnfp.Finder {
+	return &possessiveFinder{finder: finder.finder.Copy()}
+}
+
+// RFC9110 - 5.6.5. Comments
+//
+//  ctext          = HTAB / SP / %x21-27 / %x2A-5B / %x5D-7E / obs-text
+//
+
+// NOTE
+// x21-27 : ! " # $ % & '
+// x2A-5B : * + , - . / 0-9 : ; < = > ? @ A-Z [
+// x5D-7E : ] ^ _ ` a-z { | } ~
+func NewCTextFinder() abnfp.Finder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewHTabFinder(),
+		abnfp.NewSpFinder(),
+		abnfp.NewValueRangeAlternativesFinder(0x21, 0x27),
+		abnfp.NewValueRangeAlternativesFinder(0x2a, 0x5b),
+		abnfp.NewValueRangeAlternativesFinder(0x5d, 0x7e),
+		NewObsTextFinder(),
+	})
+}
+
+// RFC9110 - 7.2. Host and :authority
+//
+//  Host = uri-host [ ":" port ] ; Section 4
+//
+
+func NewHostFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewUriHostFinder(),
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(':'),
+				urip.NewPortFinder(),
+			}),
+		),
+	})
+}
+
+// RFC9110 - 7.6.3. Via
+//
+//  Via = #( received-protocol RWS received-by [ RWS comment ] )
+//
+
+func NewViaFinder() abnfp.Finder {
+	return NewListFinder(NewViaElementFinder())
+}
+
+// RFC9110 - 7.6.3. Via
+//
+//  via-element = received-protocol RWS received-by [ RWS comment ]
+//
+
+// NOTE
+// via-element is not defined in RFC9110. It is the element of the Via list.
+// received-protocol and received-by are not backtracked into, because they
+// end with a delimiter or the end of via-element.
+func NewViaElementFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		newPossessiveFinder(NewReceivedProtocolFinder()),
+		NewRwsFinder(),
+		newPossessiveFinder(NewReceivedByFinder()),
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				NewRwsFinder(),
+				NewCommentFinder(),
+			}),
+		),
+	})
+}
+
+// RFC9110 - 7.6.3. Via
+//
+//  received-protocol = [ protocol-name "/" ] protocol-version
+//                    ; see Section 7.8
+//
+
+func NewReceivedProtocolFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				NewProtocolNameFinder(),
+				abnfp.NewByteFinder('/'),
+			}),
+		),
+		NewProtocolVersionFinder(),
+	})
+}
+
+// RFC9110 - 7.6.3. Via
+//
+//  received-by       = pseudonym [ ":" port ]
+//
+
+// NOTE
+// RFC7230 defined received-by as ( uri-host [ ":" port ] ) / pseudonym. An
+// IPv6 address in brackets, which is not a token, is also accepted.
+func NewReceivedByFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			NewPseudonymFinder(),
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder('['),
+				newIpV6AddressFinder(),
+				abnfp.NewByteFinder(']'),
+			}),
+		}),
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(':'),
+				urip.NewPortFinder(),
+			}),
+		),
+	})
+}
+
+// RFC9110 - 7.6.3. Via
+//
+//  pseudonym         = token
+//
+
+func NewPseudonymFinder() abnfp.Finder {
+	return NewTokenFinder()
+}
+
+// RFC9110 - 7.8. Upgrade
+//
+//  protocol-name    = token
+//
+
+func NewProtocolNameFinder() abnfp.Finder {
+	return NewTokenFinder()
+}
+
+// RFC9110 - 7.8. Upgrade
+//
+//  protocol-version = token
+//
+
+func NewProtocolVersionFinder() abnfp.Finder {
+	return NewTokenFinder()
+}
+
+// RFC9110 - 8.6. Content-Length
+//
+//  Content-Length = 1*DIGIT
+//
+
+func NewContentLengthFinder() abnfp.Finder {
+	return abnfp.NewVariableRepetitionMinFinder(1, abnfp.NewDigitFinder())
+}
+
+// RFC9110 - 10.1.4. TE
+//
+//  transfer-coding    = token *( OWS ";" OWS transfer-parameter )
+//  transfer-parameter = token BWS "=" BWS ( token / quoted-string )
+//
+
+func NewTransferCodingFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewTokenFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				NewOwsFinder(),
+				abnfp.NewByteFinder(';'),
+				NewOwsFinder(),
+				NewTransferParameterFinder(),
+			}),
+		),
+	})
+}
+
+func NewTransferParameterFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewTokenFinder(),
+		NewBwsFinder(),
+		abnfp.NewByteFinder('='),
+		NewBwsFinder(),
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			NewTokenFinder(),
+			NewQuotedStringFinder(),
+		}),
+	})
+}
+
 // RFC9112 - 2.1. Message Format
 //
 //  HTTP-message   = start-line CRLF
@@ -349,8 +685,82 @@ func NewOriginFormFinder() abnfp.Finder {
 //  absolute-form  = absolute-URI
 //
 
+// RFC3986 - 4.3. Absolute URI
+//
+//  absolute-URI  = scheme ":" hier-part [ "?" query ]
+//
+// RFC3986 - 3. Syntax Components
+//
+//  hier-part     = "//" authority path-abempty
+//                / path-absolute
+//                / path-rootless
+//                / path-empty
+//
+// RFC3986 - 3.2. Authority
+//
+//  authority     = [ userinfo "@" ] host [ ":" port ]
+//
+
+// NOTE
+// urip.NewAbsoluteUriFinder panics on some data, e.g. "ab", and does not find
+// compressed IPv6 addresses. absoluteFormFinder parses absolute-URI component
+// by component instead, with NewUriHostFinder for host.
+
 func NewAbsoluteFormFinder() abnfp.Finder {
-	return urip.NewAbsoluteUriFinder()
+	return &absoluteFormFinder{}
+}
+
+type absoluteFormFinder struct {
+	uri absoluteUri
+}
+
+// absoluteUri is absolute-URI split into its components. The components
+// which do not appear in the absolute-URI are nil.
+type absoluteUri struct {
+	scheme   []byte
+	userInfo []byte
+	host     []byte
+	port     []byte
+	path     []byte
+	query    []byte
+}
+
+func (finder *absoluteFormFinder) Find(data []byte) (bool, int) {
+	finder.uri = absoluteUri{}
+	scheme, remaining := abnfp.Parse(data, urip.NewSchemeFinder())
+	if len(scheme) == 0 || len(remaining) == 0 || remaining[0] != ':' {
+		return false, 0
+	}
+	finder.uri.scheme = scheme
+	remaining = remaining[1:]
+
+	if bytes.HasPrefix(remaining, []byte("//")) {
+		remaining = remaining[2:]
+		userInfo, rest := abnfp.Parse(remaining, urip.NewUserInfoFinder())
+		if len(rest) > 0 && rest[0] == '@' {
+			finder.uri.userInfo = userInfo
+			remaining = rest[1:]
+		}
+		finder.uri.host, remaining = abnfp.Parse(remaining, NewUriHostFinder())
+		if len(remaining) > 0 && remaining[0] == ':' {
+			finder.uri.port, remaining = abnfp.Parse(remaining[1:], urip.NewPortFinder())
+		}
+		finder.uri.path, remaining = abnfp.Parse(remaining, urip.NewPathAbemptyFinder())
+	} else {
+		finder.uri.path, remaining = abnfp.Parse(remaining, abnfp.NewAlternativesFinder([]abnfp.Finder{
+			urip.NewPathAbsoluteFinder(),
+			urip.NewPathRootlessFinder(),
+		}))
+	}
+
+	if len(remaining) > 0 && remaining[0] == '?' {
+		finder.uri.query, remaining = abnfp.Parse(remaining[1:], urip.NewQueryFinder())
+	}
+	return true, len(data) - len(remaining)
+}
+
+func (finder *absoluteFormFinder) Copy() abnfp.Finder {
+	return &absoluteFormFinder{}
 }
 
 // RFC9112 - 3.2.3. authority-form
@@ -431,6 +841,20 @@ func NewFieldLineFinder() abnfp.Finder {
 	})
 }
 
+// RFC9112 - 5.2. Obsolete Line Folding
+//
+//  obs-fold     = OWS CRLF RWS
+//                 ; obsolete line folding
+//
+
+func NewObsFoldFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewOwsFinder(),
+		abnfp.NewCrLfFinder(),
+		NewRwsFinder(),
+	})
+}
+
 // RFC9112 - 6. Message Body
 //
 //  message-body = *OCTET
@@ -439,3 +863,348 @@ func NewFieldLineFinder() abnfp.Finder {
 func NewMessageBodyFinder() abnfp.Finder {
 	return abnfp.NewVariableRepetitionFinder(abnfp.NewOctetFinder())
 }
+
+// RFC9112 - 7.1. Chunked Transfer Coding
+//
+//  chunked-body   = *chunk
+//                   last-chunk
+//                   trailer-section
+//                   CRLF
+//
+
+func NewChunkedBodyFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewVariableRepetitionFinder(NewChunkFinder()),
+		NewLastChunkFinder(),
+		NewTrailerSectionFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC9112 - 7.1. Chunked Transfer Coding
+//
+//  chunk          = chunk-size [ chunk-ext ] CRLF
+//                   chunk-data CRLF
+//
+
+// NOTE
+// The length of chunk-data is given by chunk-size, which cannot be
+// expressed by combining the generic finders. So chunkFinder parses
+// chunk-size first and then finds exactly that many octets of chunk-data.
+type chunkFinder struct{}
+
+func (finder *chunkFinder) Find(data []byte) (found bool, end int) {
+	chunkSize, remaining := abnfp.Parse(data, NewChunkSizeFinder())
+	if len(chunkSize) == 0 {
+		return false, 0
+	}
+	size, err := strconv.ParseInt(string(chunkSize), 16, 64)
+	if err != nil || size == 0 {
+		// A chunk-size of zero is last-chunk.
+		return false, 0
+	}
+
+	chunkExt, remaining := abnfp.Parse(remaining, abnfp.NewOptionalSequenceFinder(NewChunkExtFinder()))
+	crlf, remaining := abnfp.Parse(remaining, abnfp.NewCrLfFinder())
+	if len(crlf) == 0 {
+		return false, 0
+	}
+	if int64(len(remaining)) < size {
+		return false, 0
+	}
+	remaining = remaining[size:]
+	crlf, _ = abnfp.Parse(remaining, abnfp.NewCrLfFinder())
+	if len(crlf) == 0 {
+		return false, 0
+	}
+	return true, len(chunkSize) + len(chunkExt) + 2 + int(size) + 2
+}
+
+func (finder *chunkFinder) Copy() abnfp.Finder {
+	return &chunkFinder{}
+}
+
+func NewChunkFinder() abnfp.Finder {
+	return &chunkFinder{}
+}
+
+// RFC9112 - 7.1. Chunked Transfer Coding
+//
+//  chunk-size     = 1*HEXDIG
+//
+
+// NOTE
+// Quoted strings in ABNF are case-insensitive, so HEXDIG also matches
+// "a" to "f". abnfp.NewHexDigFinder only matches the upper case letters.
+func NewChunkSizeFinder() abnfp.Finder {
+	return abnfp.NewVariableRepetitionMinFinder(
+		1,
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			abnfp.NewHexDigFinder(),
+			abnfp.NewValueRangeAlternativesFinder('a', 'f'),
+		}),
+	)
+}
+
+// RFC9112 - 7.1. Chunked Transfer Coding
+//
+//  last-chunk     = 1*("0") [ chunk-ext ] CRLF
+//
+
+func NewLastChunkFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewVariableRepetitionMinFinder(1, abnfp.NewByteFinder('0')),
+		abnfp.NewOptionalSequenceFinder(NewChunkExtFinder()),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC9112 - 7.1. Chunked Transfer Coding
+//
+//  chunk-data     = 1*OCTET ; a sequence of chunk-size octets
+//
+
+func NewChunkDataFinder() abnfp.Finder {
+	return abnfp.NewVariableRepetitionMinFinder(1, abnfp.NewOctetFinder())
+}
+
+// RFC9112 - 7.1.1. Chunk Extensions
+//
+//  chunk-ext      = *( BWS ";" BWS chunk-ext-name
+//                      [ BWS "=" BWS chunk-ext-val ] )
+//
+
+func NewChunkExtFinder() abnfp.Finder {
+	return abnfp.NewVariableRepetitionFinder(
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			NewBwsFinder(),
+			abnfp.NewByteFinder(';'),
+			NewBwsFinder(),
+			NewChunkExtNameFinder(),
+			abnfp.NewOptionalSequenceFinder(
+				abnfp.NewConcatenationFinder([]abnfp.Finder{
+					NewBwsFinder(),
+					abnfp.NewByteFinder('='),
+					NewBwsFinder(),
+					NewChunkExtValFinder(),
+				}),
+			),
+		}),
+	)
+}
+
+// RFC9112 - 7.1.1. Chunk Extensions
+//
+//  chunk-ext-name = token
+//
+
+func NewChunkExtNameFinder() abnfp.Finder {
+	return NewTokenFinder()
+}
+
+// RFC9112 - 7.1.1. Chunk Extensions
+//
+//  chunk-ext-val  = token / quoted-string
+//
+
+func NewChunkExtValFinder() abnfp.Finder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewTokenFinder(),
+		NewQuotedStringFinder(),
+	})
+}
+
+// RFC9112 - 7.1.2. Chunked Trailer Section
+//
+//  trailer-section   = *( field-line CRLF )
+//
+
+func NewTrailerSectionFinder() abnfp.Finder {
+	return abnfp.NewVariableRepetitionFinder(
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			NewFieldLineFinder(),
+			abnfp.NewCrLfFinder(),
+		}),
+	)
+}
+
+// RFC7239 - 4. Forwarded HTTP Header Field
+//
+//  Forwarded   = 1#forwarded-element
+//
+
+func NewForwardedFinder() abnfp.Finder {
+	return NewNonEmptyListFinder(NewForwardedElementFinder())
+}
+
+// RFC7239 - 4. Forwarded HTTP Header Field
+//
+//  forwarded-element =
+//      [ forwarded-pair ] *( ";" [ forwarded-pair ] )
+//
+
+func NewForwardedElementFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewForwardedPairFinder()),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(';'),
+				abnfp.NewOptionalSequenceFinder(NewForwardedPairFinder()),
+			}),
+		),
+	})
+}
+
+// RFC7239 - 4. Forwarded HTTP Header Field
+//
+//  forwarded-pair = token "=" value
+//  value          = token / quoted-string
+//
+
+func NewForwardedPairFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewTokenFinder(),
+		abnfp.NewByteFinder('='),
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			NewTokenFinder(),
+			NewQuotedStringFinder(),
+		}),
+	})
+}
+
+// RFC7239 - 6. Node Identifiers
+//
+//  node     = nodename [ ":" node-port ]
+//
+
+func NewNodeFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewNodeNameFinder(),
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(':'),
+				NewNodePortFinder(),
+			}),
+		),
+	})
+}
+
+// RFC7239 - 6. Node Identifiers
+//
+//  nodename = IPv4address / "[" IPv6address "]" /
+//             "unknown" / obfnode
+//
+
+func NewNodeNameFinder() abnfp.Finder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		urip.NewIpV4AddressFinder(),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('['),
+			newIpV6AddressFinder(),
+			abnfp.NewByteFinder(']'),
+		}),
+		newCaseInsensitiveStringFinder([]byte("unknown")),
+		NewObfNodeFinder(),
+	})
+}
+
+// RFC7239 - 6. Node Identifiers
+//
+//  node-port     = port / obfport
+//  port          = 1*5DIGIT
+//
+
+func NewNodePortFinder() abnfp.Finder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewVariableRepetitionMinMaxFinder(1, 5, abnfp.NewDigitFinder()),
+		NewObfPortFinder(),
+	})
+}
+
+// RFC7239 - 6.3. Obfuscated Identifier
+//
+//  obfnode = "_" 1*( ALPHA / DIGIT / "." / "_" / "-")
+//
+
+func NewObfNodeFinder() abnfp.Finder {
+	return newObfuscatedIdentifierFinder()
+}
+
+// RFC7239 - 6.3. Obfuscated Identifier
+//
+//  obfport = "_" 1*(ALPHA / DIGIT / "." / "_" / "-")
+//
+
+func NewObfPortFinder() abnfp.Finder {
+	return newObfuscatedIdentifierFinder()
+}
+
+func newObfuscatedIdentifierFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('_'),
+		abnfp.NewVariableRepetitionMinFinder(
+			1,
+			abnfp.NewAlternativesFinder([]abnfp.Finder{
+				abnfp.NewAlphaFinder(),
+				abnfp.NewDigitFinder(),
+				abnfp.NewByteFinder('.'),
+				abnfp.NewByteFinder('_'),
+				abnfp.NewByteFinder('-'),
+			}),
+		),
+	})
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  IPv6address =                            6( h16 ":" ) ls32
+//              /                       "::" 5( h16 ":" ) ls32
+//              / [               h16 ] "::" 4( h16 ":" ) ls32
+//              / [ *1( h16 ":" ) h16 ] "::" 3( h16 ":" ) ls32
+//              / [ *2( h16 ":" ) h16 ] "::" 2( h16 ":" ) ls32
+//              / [ *3( h16 ":" ) h16 ] "::"    h16 ":"   ls32
+//              / [ *4( h16 ":" ) h16 ] "::"              ls32
+//              / [ *5( h16 ":" ) h16 ] "::"              h16
+//              / [ *6( h16 ":" ) h16 ] "::"
+//
+
+// NOTE
+// urip.NewIpV6AddressFinder does not find some addresses, e.g.
+// "2001:db8::1", so the longest run of the characters of IPv6address is
+// checked with net.ParseIP instead.
+func newIpV6AddressFinder() abnfp.Finder {
+	return &ipV6AddressFinder{}
+}
+
+type ipV6AddressFinder struct{}
+
+func (finder *ipV6AddressFinder) Find(data []byte) (found bool, end int) {
+	hasColon := false
+	for end < len(data) {
+		b := data[end]
+		if b == ':' {
+			hasColon = true
+		} else if !isHexDigit(b) && b != '.' {
+			break
+		}
+		end++
+	}
+	if !hasColon || net.ParseIP(string(data[:end])) == nil {
+		return false, 0
+	}
+	return true, end
+}
+
+func (finder *ipV6AddressFinder) Copy() abnfp.Finder {
+	return &ipV6AddressFinder{}
+}
+
+func isHexDigit(b byte) bool {
+	return ('0' <= b && b <= '9') || ('a' <= b && b <= 'f') || ('A' <= b && b <= 'F')
+}
+
+// isTextByte reports whether b is HTAB, SP, VCHAR or obs-text, which are
+// the characters of reason-phrase and field-value.
+func isTextByte(b byte) bool {
+	return b == '\t' || (b >= ' ' && b != 0x7f)
+}
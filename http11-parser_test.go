@@ -46,6 +46,13 @@ func TestNewUriHostFinder(t *testing.T) {
 			expectedFound: true,
 			expectedEnd:   41,
 		},
+		{
+			testName:      "data: []byte(\"[2001:db8::1]\")",
+			data:          []byte("[2001:db8::1]"),
+			finder:        NewUriHostFinder(),
+			expectedFound: true,
+			expectedEnd:   13,
+		},
 		{
 			testName:      "data: []byte(\"255.255.255.255\")",
 			data:          []byte("255.255.255.255"),
@@ -268,6 +275,81 @@ func TestNewObsTextFinder(t *testing.T) {
 	execTest(tests, t)
 }
 
+func TestNewListFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewListFinder(NewTokenFinder()),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"foo, bar\")",
+			data:          []byte("foo, bar"),
+			finder:        NewListFinder(NewTokenFinder()),
+			expectedFound: true,
+			expectedEnd:   8,
+		},
+		{
+			testName:      "data: []byte(\"foo,,bar\")",
+			data:          []byte("foo,,bar"),
+			finder:        NewListFinder(NewTokenFinder()),
+			expectedFound: true,
+			expectedEnd:   8,
+		},
+		{
+			testName:      "data: []byte(\", foo\")",
+			data:          []byte(", foo"),
+			finder:        NewListFinder(NewTokenFinder()),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(\"foo bar\")",
+			data:          []byte("foo bar"),
+			finder:        NewListFinder(NewTokenFinder()),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewNonEmptyListFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewNonEmptyListFinder(NewTokenFinder()),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\",\")",
+			data:          []byte(","),
+			finder:        NewNonEmptyListFinder(NewTokenFinder()),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\", foo\")",
+			data:          []byte(", foo"),
+			finder:        NewNonEmptyListFinder(NewTokenFinder()),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(\"foo ,, bar\")",
+			data:          []byte("foo ,, bar"),
+			finder:        NewNonEmptyListFinder(NewTokenFinder()),
+			expectedFound: true,
+			expectedEnd:   10,
+		},
+	}
+	execTest(tests, t)
+}
+
 func TestNewTokenFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -708,6 +790,224 @@ func TestNewQuotePairFinder(t *testing.T) {
 	execTest(tests, t)
 }
 
+func TestNewCommentFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"\")",
+			data:          []byte(""),
+			finder:        NewCommentFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"(a)\")",
+			data:          []byte("(a)"),
+			finder:        NewCommentFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\"(a (b) \\\\) c)\")",
+			data:          []byte("(a (b) \\) c)"),
+			finder:        NewCommentFinder(),
+			expectedFound: true,
+			expectedEnd:   12,
+		},
+		{
+			testName:      "data: []byte(\"(a (b)\")",
+			data:          []byte("(a (b)"),
+			finder:        NewCommentFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"(a) b\")",
+			data:          []byte("(a) b"),
+			finder:        NewCommentFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewHostFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"www.example.com\")",
+			data:          []byte("www.example.com"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   15,
+		},
+		{
+			testName:      "data: []byte(\"www.example.com:8080\")",
+			data:          []byte("www.example.com:8080"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   20,
+		},
+		{
+			testName:      "data: []byte(\"[::1]:80\")",
+			data:          []byte("[::1]:80"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   8,
+		},
+		{
+			testName:      "data: []byte(\"[2001:db8::1]:8080\")",
+			data:          []byte("[2001:db8::1]:8080"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   18,
+		},
+		{
+			testName:      "data: []byte(\"a b\")",
+			data:          []byte("a b"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"a:b\")",
+			data:          []byte("a:b"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewViaElementFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"1.1 a\")",
+			data:          []byte("1.1 a"),
+			finder:        NewViaElementFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(\"HTTP/1.1 example.com:8080 (Apache/1.1)\")",
+			data:          []byte("HTTP/1.1 example.com:8080 (Apache/1.1)"),
+			finder:        NewViaElementFinder(),
+			expectedFound: true,
+			expectedEnd:   38,
+		},
+		{
+			testName:      "data: []byte(\"1.1 [::1]:8080\")",
+			data:          []byte("1.1 [::1]:8080"),
+			finder:        NewViaElementFinder(),
+			expectedFound: true,
+			expectedEnd:   14,
+		},
+		{
+			testName:      "data: []byte(\"1.1\")",
+			data:          []byte("1.1"),
+			finder:        NewViaElementFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"1.1 a, 1.0 b\")",
+			data:          []byte("1.1 a, 1.0 b"),
+			finder:        NewViaElementFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewContentLengthFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewContentLengthFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"-1\")",
+			data:          []byte("-1"),
+			finder:        NewContentLengthFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"1024\")",
+			data:          []byte("1024"),
+			finder:        NewContentLengthFinder(),
+			expectedFound: true,
+			expectedEnd:   4,
+		},
+		{
+			testName:      "data: []byte(\"7, 7\")",
+			data:          []byte("7, 7"),
+			finder:        NewContentLengthFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewTransferCodingFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewTransferCodingFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"chunked\")",
+			data:          []byte("chunked"),
+			finder:        NewTransferCodingFinder(),
+			expectedFound: true,
+			expectedEnd:   7,
+		},
+		{
+			testName:      "data: []byte(\"gzip ; q=\\\"1\\\"\")",
+			data:          []byte("gzip ; q=\"1\""),
+			finder:        NewTransferCodingFinder(),
+			expectedFound: true,
+			expectedEnd:   12,
+		},
+		{
+			testName:      "data: []byte(\"\\\"chunked\\\"\")",
+			data:          []byte("\"chunked\""),
+			finder:        NewTransferCodingFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewTransferParameterFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"q\")",
+			data:          []byte("q"),
+			finder:        NewTransferParameterFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"q = 1\")",
+			data:          []byte("q = 1"),
+			finder:        NewTransferParameterFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+	}
+	execTest(tests, t)
+}
+
 func TestNewHttpMessageFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -1112,6 +1412,40 @@ func TestNewFieldLineFinder(t *testing.T) {
 	execTest(tests, t)
 }
 
+func TestNewObsFoldFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"\\r\\n\")",
+			data:          []byte("\r\n"),
+			finder:        NewObsFoldFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"\\r\\n foo\")",
+			data:          []byte("\r\n foo"),
+			finder:        NewObsFoldFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\" \\r\\n\\t\\tfoo\")",
+			data:          []byte(" \r\n\t\tfoo"),
+			finder:        NewObsFoldFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(\"\\r\\n\\r\\n\")",
+			data:          []byte("\r\n\r\n"),
+			finder:        NewObsFoldFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execTest(tests, t)
+}
+
 func TestNewMessageBodyFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -1131,3 +1465,327 @@ func TestNewMessageBodyFinder(t *testing.T) {
 	}
 	execTest(tests, t)
 }
+
+func TestNewChunkedBodyFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewChunkedBodyFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"0\\r\\n\\r\\n\")",
+			data:          []byte("0\r\n\r\n"),
+			finder:        NewChunkedBodyFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName: "data: []byte(\"7\\r\\nabcdefg\\r\\n0\\r\\nExpires: 0\\r\\n\\r\\n\")",
+			data: []byte(
+				"7\r\n" + // 3
+					"abcdefg\r\n" + // 9
+					"0\r\n" + // 3
+					"Expires: 0\r\n" + // 12
+					"\r\n",
+			),
+			finder:        NewChunkedBodyFinder(),
+			expectedFound: true,
+			expectedEnd:   29,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewChunkFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewChunkFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"0\\r\\n\\r\\n\")",
+			data:          []byte("0\r\n\r\n"),
+			finder:        NewChunkFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"7\\r\\nabcdefg\\r\\n\")",
+			data:          []byte("7\r\nabcdefg\r\n"),
+			finder:        NewChunkFinder(),
+			expectedFound: true,
+			expectedEnd:   12,
+		},
+		{
+			testName:      "data: []byte(\"7;foo=bar\\r\\nabcdefg\\r\\n0\\r\\n\")",
+			data:          []byte("7;foo=bar\r\nabcdefg\r\n0\r\n"),
+			finder:        NewChunkFinder(),
+			expectedFound: true,
+			expectedEnd:   20,
+		},
+		{
+			testName:      "data: []byte(\"7\\r\\nabc\")",
+			data:          []byte("7\r\nabc"),
+			finder:        NewChunkFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewChunkSizeFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewChunkSizeFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"1aF\\r\\n\")",
+			data:          []byte("1aF\r\n"),
+			finder:        NewChunkSizeFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewLastChunkFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewLastChunkFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"000\\r\\n\")",
+			data:          []byte("000\r\n"),
+			finder:        NewLastChunkFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(\"0;foo\\r\\n\")",
+			data:          []byte("0;foo\r\n"),
+			finder:        NewLastChunkFinder(),
+			expectedFound: true,
+			expectedEnd:   7,
+		},
+		{
+			testName:      "data: []byte(\"1\\r\\n\")",
+			data:          []byte("1\r\n"),
+			finder:        NewLastChunkFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewChunkDataFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewChunkDataFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"abc\")",
+			data:          []byte("abc"),
+			finder:        NewChunkDataFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewChunkExtFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewChunkExtFinder(),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\";foo\")",
+			data:          []byte(";foo"),
+			finder:        NewChunkExtFinder(),
+			expectedFound: true,
+			expectedEnd:   4,
+		},
+		{
+			testName:      "data: []byte(\" ; foo = bar;baz=\\\"qux\\\"\")",
+			data:          []byte(" ; foo = bar;baz=\"qux\""),
+			finder:        NewChunkExtFinder(),
+			expectedFound: true,
+			expectedEnd:   22,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewChunkExtNameFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewChunkExtNameFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"foo=bar\")",
+			data:          []byte("foo=bar"),
+			finder:        NewChunkExtNameFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewChunkExtValFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewChunkExtValFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"bar\")",
+			data:          []byte("bar"),
+			finder:        NewChunkExtValFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\"\\\"b a r\\\"\")",
+			data:          []byte("\"b a r\""),
+			finder:        NewChunkExtValFinder(),
+			expectedFound: true,
+			expectedEnd:   7,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewTrailerSectionFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}",
+			data:          []byte{},
+			finder:        NewTrailerSectionFinder(),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"Expires: 0\\r\\n\\r\\n\")",
+			data:          []byte("Expires: 0\r\n\r\n"),
+			finder:        NewTrailerSectionFinder(),
+			expectedFound: true,
+			expectedEnd:   12,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewForwardedElementFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"for=192.0.2.60;proto=http;by=203.0.113.43\")",
+			data:          []byte("for=192.0.2.60;proto=http;by=203.0.113.43"),
+			finder:        NewForwardedElementFinder(),
+			expectedFound: true,
+			expectedEnd:   41,
+		},
+		{
+			testName:      "data: []byte(\"for=\\\"[2001:db8:cafe::17]:4711\\\"\")",
+			data:          []byte("for=\"[2001:db8:cafe::17]:4711\""),
+			finder:        NewForwardedElementFinder(),
+			expectedFound: true,
+			expectedEnd:   30,
+		},
+		{
+			testName:      "data: []byte(\"for=a;;by=b\")",
+			data:          []byte("for=a;;by=b"),
+			finder:        NewForwardedElementFinder(),
+			expectedFound: true,
+			expectedEnd:   11,
+		},
+		{
+			testName:      "data: []byte(\"for=a, for=b\")",
+			data:          []byte("for=a, for=b"),
+			finder:        NewForwardedElementFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+	}
+	execTest(tests, t)
+}
+
+func TestNewNodeFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"192.0.2.43\")",
+			data:          []byte("192.0.2.43"),
+			finder:        NewNodeFinder(),
+			expectedFound: true,
+			expectedEnd:   10,
+		},
+		{
+			testName:      "data: []byte(\"[2001:db8:cafe::17]:4711\")",
+			data:          []byte("[2001:db8:cafe::17]:4711"),
+			finder:        NewNodeFinder(),
+			expectedFound: true,
+			expectedEnd:   24,
+		},
+		{
+			testName:      "data: []byte(\"unknown\")",
+			data:          []byte("unknown"),
+			finder:        NewNodeFinder(),
+			expectedFound: true,
+			expectedEnd:   7,
+		},
+		{
+			testName:      "data: []byte(\"UNKNOWN\")",
+			data:          []byte("UNKNOWN"),
+			finder:        NewNodeFinder(),
+			expectedFound: true,
+			expectedEnd:   7,
+		},
+		{
+			testName:      "data: []byte(\"_hidden:_p\")",
+			data:          []byte("_hidden:_p"),
+			finder:        NewNodeFinder(),
+			expectedFound: true,
+			expectedEnd:   10,
+		},
+		{
+			testName:      "data: []byte(\"2001:db8:cafe::17\")",
+			data:          []byte("2001:db8:cafe::17"),
+			finder:        NewNodeFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execTest(tests, t)
+}
@@ -0,0 +1,221 @@
+package http11p
+
+import (
+	"bytes"
+	"strconv"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC9110 - 8.6. Content-Length
+//
+//  Content-Length = 1*DIGIT
+//
+// A sender MUST NOT forward a message with a Content-Length header field
+// value that does not match the ABNF above, with one exception: a recipient
+// of a Content-Length header field value consisting of the same decimal
+// value repeated as a comma-separated list (e.g, "Content-Length: 42, 42")
+// MAY either reject the message as invalid or replace that invalid field
+// value with a single instance of the decimal value.
+//
+
+func getContentLength(fieldLines FieldSection) (length int64, found bool, err error) {
+	for i, fieldLine := range fieldLines {
+		if !fieldNameEquals(fieldLine.FieldName, "Content-Length") {
+			continue
+		}
+		for _, element := range bytes.Split(fieldLine.FieldValue, []byte(",")) {
+			_, element = abnfp.Parse(element, NewOwsFinder())
+			value, rest := abnfp.Parse(element, NewContentLengthFinder())
+			_, rest = abnfp.Parse(rest, NewOwsFinder())
+			if len(value) == 0 || len(rest) != 0 {
+				return 0, found, newFieldLineError(ErrInvalidContentLength, "Content-Length", "invalid Content-Length", i)
+			}
+			parsed, parseErr := strconv.ParseInt(string(value), 10, 64)
+			if parseErr != nil {
+				return 0, found, newFieldLineError(ErrInvalidContentLength, "Content-Length", "invalid Content-Length", i)
+			}
+			if found && parsed != length {
+				return 0, found, newFieldLineError(
+					ErrConflictingContentLength,
+					"Content-Length",
+					"conflicting Content-Length values",
+					i,
+				)
+			}
+			length = parsed
+			found = true
+		}
+	}
+	return
+}
+
+// RFC9112 - 6.3. Message Body Length
+//
+// The length of a message body is determined by one of the following (in
+// order of precedence):
+//
+//  1. Any response to a HEAD request and any response with a 1xx
+//     (Informational), 204 (No Content), or 304 (Not Modified) status code
+//     is always terminated by the first empty line after the header fields.
+//  2. Any 2xx (Successful) response to a CONNECT request implies that the
+//     connection will become a tunnel immediately after the empty line that
+//     concludes the header fields. A client MUST ignore any Content-Length
+//     or Transfer-Encoding header fields received in such a message.
+//  3. If a Transfer-Encoding header field is present and the chunked
+//     transfer coding is the final encoding, the message body length is
+//     determined by reading and decoding the chunked data until the
+//     transfer coding indicates the data is complete.
+//     If a Transfer-Encoding header field is present in a response and the
+//     chunked transfer coding is not the final encoding, the message body
+//     length is determined by reading the connection until it is closed by
+//     the server.
+//     If a Transfer-Encoding header field is present in a request and the
+//     chunked transfer coding is not the final encoding, the message body
+//     length cannot be determined reliably; the server MUST respond with
+//     the 400 (Bad Request) status code and then close the connection.
+//  4. If a message is received without Transfer-Encoding and with an
+//     invalid Content-Length header field, then the message framing is
+//     invalid and the recipient MUST treat it as an unrecoverable error.
+//  5. If a valid Content-Length header field is present without
+//     Transfer-Encoding, its decimal value defines the expected message
+//     body length in octets.
+//  6. If this is a request message and none of the above are true, then
+//     the message body length is zero.
+//  7. Otherwise, this is a response message without a declared message
+//     body length, so the message body length is determined by the number
+//     of octets received prior to the server closing the connection.
+//
+
+type bodyFraming int
+
+const (
+	// The message has no message-body.
+	framingNone bodyFraming = iota
+	// The message-body length is given by Content-Length.
+	framingContentLength
+	// The message-body is sent with the chunked transfer coding.
+	framingChunked
+	// The message-body is delimited by the closing of the connection.
+	framingUntilClose
+	// The message has no message-body and the connection becomes a tunnel
+	// after the header section.
+	framingTunnel
+)
+
+// RFC9112 - 6.1. Transfer-Encoding
+//
+// A server or client that receives an HTTP/1.0 message containing a
+// Transfer-Encoding header field MUST treat the message as if the framing
+// is faulty, even if a Content-Length is present, and close the connection
+// after processing the message.
+//
+
+// checkTransferEncodingVersion returns an error if fieldLines contains
+// Transfer-Encoding and the message is HTTP/1.0. An invalid httpVersion is
+// checked by the parsers of the start-line.
+//
+// NOTE
+// The parsers reject such a request, to which a server responds 400. They
+// read the message-body of such a response until the connection is closed,
+// as getResponseBodyFraming tells, and IsPersistent tells the client to
+// close the connection after it.
+func checkTransferEncodingVersion(httpVersion []byte, fieldLines FieldSection) error {
+	version, err := ParseHttpVersion(httpVersion)
+	if err != nil || version.SupportsTransferEncoding() || !fieldLines.Has("Transfer-Encoding") {
+		return nil
+	}
+	return newFieldLineError(
+		ErrTransferEncodingInHttp10,
+		"Transfer-Encoding",
+		"Transfer-Encoding in HTTP/1.0",
+		lastFieldLine(fieldLines, "Transfer-Encoding"),
+	)
+}
+
+func getBodyFraming(fieldLines FieldSection, isRequest bool) (framing bodyFraming, length int64, err error) {
+	if fieldLines.Has("Transfer-Encoding") {
+		if isChunked(fieldLines) {
+			return framingChunked, 0, nil
+		}
+		if isRequest {
+			return framingNone, 0, newFieldLineError(
+				ErrChunkedNotFinal,
+				"Transfer-Encoding",
+				"chunked is not the final transfer coding",
+				lastFieldLine(fieldLines, "Transfer-Encoding"),
+			)
+		}
+		return framingUntilClose, 0, nil
+	}
+
+	length, found, err := getContentLength(fieldLines)
+	if err != nil {
+		return framingNone, 0, err
+	}
+	if found {
+		return framingContentLength, length, nil
+	}
+
+	if isRequest {
+		return framingNone, 0, nil
+	}
+	return framingUntilClose, 0, nil
+}
+
+// getResponseBodyFraming applies the rules 1 and 2, which depend on the
+// request method and the status code, before the others. method can be nil
+// if the request method is unknown. The framing of an HTTP/1.0 response with
+// Transfer-Encoding is faulty, so its message-body is delimited by the
+// closing of the connection.
+func getResponseBodyFraming(method []byte, httpVersion []byte, statusCode []byte, fieldLines FieldSection) (
+	framing bodyFraming,
+	length int64,
+	err error,
+) {
+	code, _ := parseStatusCode(statusCode)
+	if Method(method) == MethodHead || code.IsInformational() || code == StatusNoContent || code == StatusNotModified {
+		return framingNone, 0, nil
+	}
+	if Method(method) == MethodConnect && code.IsSuccessful() {
+		return framingTunnel, 0, nil
+	}
+	if checkTransferEncodingVersion(httpVersion, fieldLines) != nil {
+		return framingUntilClose, 0, nil
+	}
+	return getBodyFraming(fieldLines, false)
+}
+
+func marshalMessageBody(data []byte, framing bodyFraming, length int64, options ParserOptions) (
+	body []byte,
+	chunkExtensions []ChunkExtension,
+	trailerSection []FieldLine,
+	remaining []byte,
+	err error,
+) {
+	switch framing {
+	case framingContentLength:
+		err = options.checkBodySize(length, data)
+		if err != nil {
+			return nil, nil, nil, data, err
+		}
+		if int64(len(data)) < length {
+			return nil, nil, nil, data, newParseError(
+				ErrIncompleteMessage,
+				"message-body",
+				"message-body shorter than Content-Length",
+				data[len(data):],
+			)
+		}
+		return data[:length], nil, nil, data[length:], nil
+	case framingChunked:
+		return marshalChunkedBody(data, options)
+	case framingUntilClose:
+		err = options.checkBodySize(int64(len(data)), data)
+		if err != nil {
+			return nil, nil, nil, data, err
+		}
+		return data, nil, nil, []byte{}, nil
+	}
+	return []byte{}, nil, nil, data, nil
+}
@@ -0,0 +1,353 @@
+package http11p
+
+import "testing"
+
+func TestGetContentLength(t *testing.T) {
+	type TestCaseGetContentLength struct {
+		testName       string
+		fieldLines     []FieldLine
+		err            bool
+		expectedLength int64
+		expectedFound  bool
+	}
+
+	tests := []TestCaseGetContentLength{
+		{
+			testName:       "no Content-Length",
+			fieldLines:     []FieldLine{},
+			err:            false,
+			expectedLength: 0,
+			expectedFound:  false,
+		},
+		{
+			testName: "Content-Length: 7",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+			},
+			err:            false,
+			expectedLength: 7,
+			expectedFound:  true,
+		},
+		{
+			testName: "content-length: 7",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("content-length"), FieldValue: []byte("7")},
+			},
+			err:            false,
+			expectedLength: 7,
+			expectedFound:  true,
+		},
+		{
+			testName: "Content-Length: 7, 7",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7, 7")},
+			},
+			err:            false,
+			expectedLength: 7,
+			expectedFound:  true,
+		},
+		{
+			testName: "Content-Length: 7 and Content-Length: 7",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+			},
+			err:            false,
+			expectedLength: 7,
+			expectedFound:  true,
+		},
+		{
+			testName: "Content-Length: 7, 8",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7, 8")},
+			},
+			err: true,
+		},
+		{
+			testName: "Content-Length: 7 and Content-Length: 8",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("8")},
+			},
+			err: true,
+		},
+		{
+			testName: "Content-Length: -1",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("-1")},
+			},
+			err: true,
+		},
+		{
+			testName: "Content-Length: 7a",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7a")},
+			},
+			err: true,
+		},
+		{
+			testName: "Content-Length: 99999999999999999999",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("99999999999999999999")},
+			},
+			err: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			length, found, err := getContentLength(testCase.fieldLines)
+			if err != nil {
+				if !testCase.err {
+					t.Errorf("Failed to get Content-Length: %v", err.Error())
+				}
+				return
+			}
+			if testCase.err {
+				t.Errorf("Unexpectedly get Content-Length successfully: %v", length)
+				return
+			}
+			equals(testCase.testName, t, testCase.expectedLength, length)
+			equals(testCase.testName, t, testCase.expectedFound, found)
+		})
+	}
+}
+
+func TestMarshalMessageBody(t *testing.T) {
+	type TestCaseMarshalMessageBody struct {
+		testName          string
+		data              []byte
+		fieldLines        []FieldLine
+		isRequest         bool
+		err               bool
+		expectedBody      []byte
+		expectedRemaining []byte
+	}
+
+	tests := []TestCaseMarshalMessageBody{
+		{
+			testName:          "request without Content-Length",
+			data:              []byte("GET / HTTP/1.1\r\n\r\n"),
+			fieldLines:        []FieldLine{},
+			isRequest:         true,
+			err:               false,
+			expectedBody:      []byte{},
+			expectedRemaining: []byte("GET / HTTP/1.1\r\n\r\n"),
+		},
+		{
+			testName:          "response without Content-Length",
+			data:              []byte("abcdefg"),
+			fieldLines:        []FieldLine{},
+			isRequest:         false,
+			err:               false,
+			expectedBody:      []byte("abcdefg"),
+			expectedRemaining: []byte{},
+		},
+		{
+			testName: "Content-Length equals to data length",
+			data:     []byte("abcdefg"),
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+			},
+			isRequest:         true,
+			err:               false,
+			expectedBody:      []byte("abcdefg"),
+			expectedRemaining: []byte{},
+		},
+		{
+			testName: "Content-Length shorter than data length",
+			data:     []byte("abcdefgGET / HTTP/1.1\r\n\r\n"),
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+			},
+			isRequest:         true,
+			err:               false,
+			expectedBody:      []byte("abcdefg"),
+			expectedRemaining: []byte("GET / HTTP/1.1\r\n\r\n"),
+		},
+		{
+			testName: "Content-Length longer than data length",
+			data:     []byte("abc"),
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+			},
+			isRequest: true,
+			err:       true,
+		},
+		{
+			testName: "chunked request",
+			data:     []byte("7\r\nabcdefg\r\n0\r\n\r\nnext"),
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked")},
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("3")},
+			},
+			isRequest:         true,
+			err:               false,
+			expectedBody:      []byte("abcdefg"),
+			expectedRemaining: []byte("next"),
+		},
+		{
+			testName: "request with chunked not final",
+			data:     []byte("abcdefg"),
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked, gzip")},
+			},
+			isRequest: true,
+			err:       true,
+		},
+		{
+			testName: "response with chunked not final",
+			data:     []byte("abcdefg"),
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("gzip")},
+			},
+			isRequest:         false,
+			err:               false,
+			expectedBody:      []byte("abcdefg"),
+			expectedRemaining: []byte{},
+		},
+		{
+			testName: "invalid Content-Length",
+			data:     []byte("abcdefg"),
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("seven")},
+			},
+			isRequest: false,
+			err:       true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			framing, length, err := getBodyFraming(testCase.fieldLines, testCase.isRequest)
+			var body, remaining []byte
+			if err == nil {
+				body, _, _, remaining, err = marshalMessageBody(testCase.data, framing, length, DefaultParserOptions())
+			}
+			if err != nil {
+				if !testCase.err {
+					t.Errorf("Failed to marshal message-body: %v", err.Error())
+				}
+				return
+			}
+			if testCase.err {
+				t.Errorf("Unexpectedly marshal message-body successfully: %s", body)
+				return
+			}
+			if !byteEquals(testCase.expectedBody, body) {
+				t.Errorf("expectedBody: %s, actual: %s", testCase.expectedBody, body)
+			}
+			if !byteEquals(testCase.expectedRemaining, remaining) {
+				t.Errorf("expectedRemaining: %s, actual: %s", testCase.expectedRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestGetResponseBodyFraming(t *testing.T) {
+	type TestCaseGetResponseBodyFraming struct {
+		testName        string
+		method          []byte
+		httpVersion     []byte
+		statusCode      []byte
+		fieldLines      []FieldLine
+		expectedFraming bodyFraming
+	}
+
+	contentLength := []FieldLine{
+		{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+	}
+	chunked := []FieldLine{
+		{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked")},
+	}
+
+	tests := []TestCaseGetResponseBodyFraming{
+		{
+			testName:        "unknown method, 200, Content-Length",
+			method:          nil,
+			statusCode:      []byte("200"),
+			fieldLines:      contentLength,
+			expectedFraming: framingContentLength,
+		},
+		{
+			testName:        "unknown method, 200, no Content-Length",
+			method:          nil,
+			statusCode:      []byte("200"),
+			fieldLines:      []FieldLine{},
+			expectedFraming: framingUntilClose,
+		},
+		{
+			testName:        "HEAD, 200, Content-Length",
+			method:          []byte("HEAD"),
+			statusCode:      []byte("200"),
+			fieldLines:      contentLength,
+			expectedFraming: framingNone,
+		},
+		{
+			testName:        "GET, 100, no Content-Length",
+			method:          []byte("GET"),
+			statusCode:      []byte("100"),
+			fieldLines:      []FieldLine{},
+			expectedFraming: framingNone,
+		},
+		{
+			testName:        "GET, 204, chunked",
+			method:          []byte("GET"),
+			statusCode:      []byte("204"),
+			fieldLines:      chunked,
+			expectedFraming: framingNone,
+		},
+		{
+			testName:        "GET, 304, Content-Length",
+			method:          []byte("GET"),
+			statusCode:      []byte("304"),
+			fieldLines:      contentLength,
+			expectedFraming: framingNone,
+		},
+		{
+			testName:        "CONNECT, 200, Content-Length",
+			method:          []byte("CONNECT"),
+			statusCode:      []byte("200"),
+			fieldLines:      contentLength,
+			expectedFraming: framingTunnel,
+		},
+		{
+			testName:        "CONNECT, 407, Content-Length",
+			method:          []byte("CONNECT"),
+			statusCode:      []byte("407"),
+			fieldLines:      contentLength,
+			expectedFraming: framingContentLength,
+		},
+		{
+			testName:        "GET, HTTP/1.0 200, chunked",
+			method:          []byte("GET"),
+			httpVersion:     []byte("HTTP/1.0"),
+			statusCode:      []byte("200"),
+			fieldLines:      chunked,
+			expectedFraming: framingUntilClose,
+		},
+		{
+			testName:        "head, 200, chunked",
+			method:          []byte("head"),
+			statusCode:      []byte("200"),
+			fieldLines:      chunked,
+			expectedFraming: framingChunked,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			framing, _, err := getResponseBodyFraming(
+				testCase.method,
+				testCase.httpVersion,
+				testCase.statusCode,
+				testCase.fieldLines,
+			)
+			if err != nil {
+				t.Errorf("Failed to get framing: %v", err.Error())
+				return
+			}
+			equals(testCase.testName, t, testCase.expectedFraming, framing)
+		})
+	}
+}
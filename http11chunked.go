@@ -0,0 +1,245 @@
+package http11p
+
+import (
+	"bytes"
+	"strconv"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC9112 - 7.1.1. Chunk Extensions
+//
+//  chunk-ext      = *( BWS ";" BWS chunk-ext-name
+//                      [ BWS "=" BWS chunk-ext-val ] )
+//
+
+type ChunkExtension struct {
+	ChunkExtName []byte
+	ChunkExtVal  []byte
+}
+
+func marshalChunkExt(data []byte) (chunkExtensions []ChunkExtension) {
+	chunkExtensions = []ChunkExtension{}
+	remaining := data
+	var chunkExtName []byte
+	var chunkExtVal []byte
+	var equal []byte
+
+	for len(remaining) > 0 {
+		// BWS ";" BWS
+		_, remaining = abnfp.Parse(remaining, NewBwsFinder())
+		_, remaining = abnfp.Parse(remaining, abnfp.NewByteFinder(';'))
+		_, remaining = abnfp.Parse(remaining, NewBwsFinder())
+
+		// chunk-ext-name
+		chunkExtName, remaining = abnfp.Parse(remaining, NewChunkExtNameFinder())
+		if len(chunkExtName) == 0 {
+			break
+		}
+
+		// [ BWS "=" BWS chunk-ext-val ]
+		chunkExtVal = nil
+		equal, _ = abnfp.Parse(remaining, abnfp.NewConcatenationFinder([]abnfp.Finder{
+			NewBwsFinder(),
+			abnfp.NewByteFinder('='),
+		}))
+		if len(equal) > 0 {
+			remaining = remaining[len(equal):]
+			_, remaining = abnfp.Parse(remaining, NewBwsFinder())
+			chunkExtVal, remaining = abnfp.Parse(remaining, NewChunkExtValFinder())
+		}
+
+		chunkExtensions = append(
+			chunkExtensions,
+			ChunkExtension{ChunkExtName: chunkExtName, ChunkExtVal: chunkExtVal},
+		)
+	}
+	return
+}
+
+// RFC9112 - 6.1. Transfer-Encoding
+//
+//  Transfer-Encoding = #transfer-coding
+//
+// If any transfer coding other than chunked is applied to a request's
+// content, the sender MUST apply chunked as the final transfer coding.
+//
+
+func isChunked(fieldLines FieldSection) bool {
+	transferCodings := fieldLines.ListMembers("Transfer-Encoding")
+	if len(transferCodings) == 0 {
+		return false
+	}
+	lastCoding, _ := abnfp.Parse(transferCodings[len(transferCodings)-1], NewTokenFinder())
+	return bytes.EqualFold(lastCoding, []byte("chunked"))
+}
+
+// RFC9112 - 7.1. Chunked Transfer Coding
+//
+//  chunked-body   = *chunk
+//                   last-chunk
+//                   trailer-section
+//                   CRLF
+//
+//  chunk          = chunk-size [ chunk-ext ] CRLF
+//                   chunk-data CRLF
+//  chunk-size     = 1*HEXDIG
+//  last-chunk     = 1*("0") [ chunk-ext ] CRLF
+//
+
+func marshalChunkedBody(data []byte, options ParserOptions) (
+	body []byte,
+	chunkExtensions []ChunkExtension,
+	trailerSection []FieldLine,
+	remaining []byte,
+	err error,
+) {
+	body = []byte{}
+	chunkExtensions = []ChunkExtension{}
+	remaining = data
+	var chunkSize []byte
+	var chunkExt []byte
+	var crlf []byte
+
+	for {
+		err = options.checkChunkSizeLine(lineLength(remaining), remaining)
+		if err != nil {
+			return nil, nil, nil, data, err
+		}
+
+		// chunk-size
+		rest := remaining
+		chunkSizeLine := remaining
+		chunkSize, remaining = abnfp.Parse(remaining, NewChunkSizeFinder())
+		if len(chunkSize) == 0 {
+			err = newParseError(ErrInvalidChunkedBody, "chunk-size", "chunk-size not found", rest)
+			return nil, nil, nil, data, orIncomplete(err, "chunk-size line", chunkSizeLine, newChunkSizeLineFinder(), isChunkSizeLinePrefix)
+		}
+		size, parseErr := strconv.ParseInt(string(chunkSize), 16, 64)
+		if parseErr != nil {
+			return nil, nil, nil, data, newParseError(ErrInvalidChunkedBody, "chunk-size", "invalid chunk-size", rest)
+		}
+
+		// [ chunk-ext ]
+		chunkExt, remaining = abnfp.Parse(remaining, abnfp.NewOptionalSequenceFinder(NewChunkExtFinder()))
+		chunkExtensions = append(chunkExtensions, marshalChunkExt(chunkExt)...)
+
+		// CRLF
+		rest = remaining
+		crlf, remaining = abnfp.Parse(remaining, options.newEolFinder())
+		if len(crlf) == 0 {
+			err = newParseError(ErrInvalidChunkedBody, "CRLF", "CRLF after chunk-size not found", rest)
+			return nil, nil, nil, data, orIncomplete(err, "chunk-size line", chunkSizeLine, newChunkSizeLineFinder(), isChunkSizeLinePrefix)
+		}
+
+		if size == 0 {
+			// last-chunk
+			break
+		}
+
+		// chunk-data
+		err = options.checkBodySize(addBodySize(int64(len(body)), size), data)
+		if err != nil {
+			return nil, nil, nil, data, err
+		}
+		if int64(len(remaining)) < size {
+			return nil, nil, nil, data, newParseError(
+				ErrIncompleteMessage,
+				"chunk-data",
+				"chunk-data shorter than chunk-size",
+				remaining[len(remaining):],
+			)
+		}
+		body = append(body, remaining[:size]...)
+		remaining = remaining[size:]
+
+		// CRLF
+		rest = remaining
+		crlf, remaining = abnfp.Parse(remaining, options.newEolFinder())
+		if len(crlf) == 0 {
+			if isPartOfEmptyLine(rest, options) {
+				return nil, nil, nil, data, newParseError(ErrIncompleteMessage, "CRLF", "message ends before CRLF after chunk-data", rest)
+			}
+			return nil, nil, nil, data, newParseError(ErrInvalidChunkedBody, "CRLF", "CRLF after chunk-data not found", rest)
+		}
+	}
+
+	// trailer-section
+	trailerSection, remaining, err = marshalFieldLines(remaining, options)
+	if err != nil {
+		return nil, nil, nil, data, err
+	}
+
+	// CRLF
+	rest := remaining
+	crlf, remaining = abnfp.Parse(remaining, options.newEolFinder())
+	if len(crlf) == 0 {
+		return nil, nil, nil, data, newParseError(ErrIncompleteMessage, "CRLF", "message ends before the end of the trailer-section", rest)
+	}
+	return
+}
+
+// newChunkSizeLineFinder returns a Finder of chunk-size [ chunk-ext ].
+func newChunkSizeLineFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewChunkSizeFinder(),
+		abnfp.NewOptionalSequenceFinder(NewChunkExtFinder()),
+	})
+}
+
+// isChunkSizeLinePrefix reports whether data is the part of
+// chunk-size [ chunk-ext ] received so far. It only checks that chunk-ext
+// consists of its characters.
+func isChunkSizeLinePrefix(data []byte) bool {
+	chunkSize, remaining := abnfp.Parse(data, NewChunkSizeFinder())
+	if len(remaining) == 0 {
+		return true
+	}
+	if len(chunkSize) == 0 {
+		return false
+	}
+	for _, b := range remaining {
+		if !isTextByte(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// unmarshalChunkedBody encodes body as a single chunk followed by
+// last-chunk. All chunkExtensions are attached to the first chunk.
+func unmarshalChunkedBody(
+	body []byte,
+	chunkExtensions []ChunkExtension,
+	trailerSection []FieldLine,
+) (data []byte) {
+	crlf := []byte("\r\n")
+
+	if len(body) > 0 {
+		data = append(data, []byte(strconv.FormatInt(int64(len(body)), 16))...)
+		data = append(data, unmarshalChunkExt(chunkExtensions)...)
+		data = append(data, crlf...)
+		data = append(data, body...)
+		data = append(data, crlf...)
+		chunkExtensions = nil
+	}
+
+	data = append(data, '0')
+	data = append(data, unmarshalChunkExt(chunkExtensions)...)
+	data = append(data, crlf...)
+	data = append(data, unmarshalFieldLines(trailerSection)...)
+	data = append(data, crlf...)
+	return
+}
+
+func unmarshalChunkExt(chunkExtensions []ChunkExtension) (data []byte) {
+	for _, chunkExtension := range chunkExtensions {
+		data = append(data, ';')
+		data = append(data, chunkExtension.ChunkExtName...)
+		if len(chunkExtension.ChunkExtVal) > 0 {
+			data = append(data, '=')
+			data = append(data, chunkExtension.ChunkExtVal...)
+		}
+	}
+	return
+}
@@ -0,0 +1,282 @@
+package http11p
+
+import "testing"
+
+func TestMarshalChunkExt(t *testing.T) {
+	type TestCaseMarshalChunkExt struct {
+		testName                string
+		data                    []byte
+		expectedChunkExtensions []ChunkExtension
+	}
+
+	tests := []TestCaseMarshalChunkExt{
+		{
+			testName:                "data: []byte{}",
+			data:                    []byte{},
+			expectedChunkExtensions: []ChunkExtension{},
+		},
+		{
+			testName: "data: []byte(\";foo\")",
+			data:     []byte(";foo"),
+			expectedChunkExtensions: []ChunkExtension{
+				{ChunkExtName: []byte("foo")},
+			},
+		},
+		{
+			testName: "data: []byte(\" ; foo = bar;baz=\\\"q u x\\\"\")",
+			data:     []byte(" ; foo = bar;baz=\"q u x\""),
+			expectedChunkExtensions: []ChunkExtension{
+				{ChunkExtName: []byte("foo"), ChunkExtVal: []byte("bar")},
+				{ChunkExtName: []byte("baz"), ChunkExtVal: []byte("\"q u x\"")},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			chunkExtensions := marshalChunkExt(testCase.data)
+			if len(testCase.expectedChunkExtensions) != len(chunkExtensions) {
+				t.Errorf("expectedChunkExtensions: %s, actual: %s",
+					testCase.expectedChunkExtensions, chunkExtensions)
+				return
+			}
+			for i, expected := range testCase.expectedChunkExtensions {
+				if !byteEquals(expected.ChunkExtName, chunkExtensions[i].ChunkExtName) ||
+					!byteEquals(expected.ChunkExtVal, chunkExtensions[i].ChunkExtVal) {
+					t.Errorf("expectedChunkExtension: %s, actual: %s", expected, chunkExtensions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsChunked(t *testing.T) {
+	type TestCaseIsChunked struct {
+		testName   string
+		fieldLines []FieldLine
+		expected   bool
+	}
+
+	tests := []TestCaseIsChunked{
+		{
+			testName:   "no Transfer-Encoding",
+			fieldLines: []FieldLine{},
+			expected:   false,
+		},
+		{
+			testName: "Transfer-Encoding: chunked",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked")},
+			},
+			expected: true,
+		},
+		{
+			testName: "transfer-encoding: gzip, Chunked",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("transfer-encoding"), FieldValue: []byte("gzip, Chunked")},
+			},
+			expected: true,
+		},
+		{
+			testName: "Transfer-Encoding: chunked, gzip",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked, gzip")},
+			},
+			expected: false,
+		},
+		{
+			testName: "Transfer-Encoding: gzip and Transfer-Encoding: chunked",
+			fieldLines: []FieldLine{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("gzip")},
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked")},
+			},
+			expected: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			equals(testCase.testName, t, testCase.expected, isChunked(testCase.fieldLines))
+		})
+	}
+}
+
+func TestMarshalChunkedBody(t *testing.T) {
+	type TestCaseMarshalChunkedBody struct {
+		testName                string
+		data                    []byte
+		err                     bool
+		expectedBody            []byte
+		expectedChunkExtensions []ChunkExtension
+		expectedTrailerSection  []FieldLine
+		expectedRemaining       []byte
+	}
+
+	tests := []TestCaseMarshalChunkedBody{
+		{
+			testName: "data: []byte{}",
+			data:     []byte{},
+			err:      true,
+		},
+		{
+			testName:                "last-chunk only",
+			data:                    []byte("0\r\n\r\n"),
+			err:                     false,
+			expectedBody:            []byte{},
+			expectedChunkExtensions: []ChunkExtension{},
+			expectedTrailerSection:  []FieldLine{},
+			expectedRemaining:       []byte{},
+		},
+		{
+			testName: "chunks, chunk-ext and trailer-section",
+			data: []byte(
+				"7;foo=bar\r\n" +
+					"abcdefg\r\n" +
+					"A\r\n" +
+					"0123456789\r\n" +
+					"000;baz\r\n" +
+					"Expires: Wed, 21 Oct 2015 07:28:00 GMT\r\n" +
+					"\r\n" +
+					"GET / HTTP/1.1\r\n\r\n",
+			),
+			err:          false,
+			expectedBody: []byte("abcdefg0123456789"),
+			expectedChunkExtensions: []ChunkExtension{
+				{ChunkExtName: []byte("foo"), ChunkExtVal: []byte("bar")},
+				{ChunkExtName: []byte("baz")},
+			},
+			expectedTrailerSection: []FieldLine{
+				{FieldName: []byte("Expires"), FieldValue: []byte("Wed, 21 Oct 2015 07:28:00 GMT")},
+			},
+			expectedRemaining: []byte("GET / HTTP/1.1\r\n\r\n"),
+		},
+		{
+			testName: "chunk-data shorter than chunk-size",
+			data:     []byte("7\r\nabc"),
+			err:      true,
+		},
+		{
+			testName: "chunk-data longer than chunk-size",
+			data:     []byte("3\r\nabcdefg\r\n0\r\n\r\n"),
+			err:      true,
+		},
+		{
+			testName: "last-chunk without final CRLF",
+			data:     []byte("3\r\nabc\r\n0\r\n"),
+			err:      true,
+		},
+		{
+			testName: "chunk-size overflow",
+			data:     []byte("fffffffffffffffffff\r\nabc\r\n0\r\n\r\n"),
+			err:      true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			body, chunkExtensions, trailerSection, remaining, err := marshalChunkedBody(testCase.data, DefaultParserOptions())
+			if err != nil {
+				if !testCase.err {
+					t.Errorf("Failed to marshal chunked-body: %v", err.Error())
+				}
+				return
+			}
+			if testCase.err {
+				t.Errorf("Unexpectedly marshal chunked-body successfully: %s", body)
+				return
+			}
+			if !byteEquals(testCase.expectedBody, body) {
+				t.Errorf("expectedBody: %s, actual: %s", testCase.expectedBody, body)
+			}
+			if len(testCase.expectedChunkExtensions) != len(chunkExtensions) {
+				t.Errorf("expectedChunkExtensions: %s, actual: %s",
+					testCase.expectedChunkExtensions, chunkExtensions)
+			}
+			if len(testCase.expectedTrailerSection) != len(trailerSection) {
+				t.Errorf("expectedTrailerSection: %s, actual: %s",
+					testCase.expectedTrailerSection, trailerSection)
+			}
+			for i, expected := range testCase.expectedTrailerSection {
+				if !byteEquals(expected.FieldValue, trailerSection[i].FieldValue) {
+					t.Errorf("expectedTrailerSection: %s, actual: %s", expected, trailerSection[i])
+				}
+			}
+			if !byteEquals(testCase.expectedRemaining, remaining) {
+				t.Errorf("expectedRemaining: %s, actual: %s", testCase.expectedRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestUnmarshalChunkedBody(t *testing.T) {
+	type TestCaseUnmarshalChunkedBody struct {
+		testName        string
+		body            []byte
+		chunkExtensions []ChunkExtension
+		trailerSection  []FieldLine
+		expectedBytes   []byte
+	}
+
+	tests := []TestCaseUnmarshalChunkedBody{
+		{
+			testName:      "empty body",
+			body:          []byte{},
+			expectedBytes: []byte("0\r\n\r\n"),
+		},
+		{
+			testName: "body, chunk-ext and trailer-section",
+			body:     []byte("abcdefg0123456789"),
+			chunkExtensions: []ChunkExtension{
+				{ChunkExtName: []byte("foo"), ChunkExtVal: []byte("bar")},
+			},
+			trailerSection: []FieldLine{
+				{FieldName: []byte("Expires"), FieldValue: []byte("0")},
+			},
+			expectedBytes: []byte(
+				"11;foo=bar\r\n" +
+					"abcdefg0123456789\r\n" +
+					"0\r\n" +
+					"Expires: 0\r\n" +
+					"\r\n",
+			),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actualBytes := unmarshalChunkedBody(testCase.body, testCase.chunkExtensions, testCase.trailerSection)
+			if !byteEquals(testCase.expectedBytes, actualBytes) {
+				t.Errorf("expected: %q, actual: %q", testCase.expectedBytes, actualBytes)
+			}
+		})
+	}
+}
+
+// The chunk-ext of all the chunks are sent with the single chunk of
+// Unmarshal.
+func TestChunkExtensionsRoundTrip(t *testing.T) {
+	data := []byte(
+		"POST / HTTP/1.1\r\n" +
+			"Transfer-Encoding: chunked\r\n" +
+			"\r\n" +
+			"3;a=1\r\nabc\r\n" +
+			"4;b\r\ndefg\r\n" +
+			"0;c\r\n" +
+			"\r\n",
+	)
+	var req Http11Request
+	err := req.Marshal(data)
+	if err != nil {
+		t.Errorf("Failed to marshal Http/1.1 Request: %v", err.Error())
+		return
+	}
+	equals("len(ChunkExtensions)", t, 3, len(req.ChunkExtensions))
+
+	expected := "POST / HTTP/1.1\r\n" +
+		"Transfer-Encoding: chunked\r\n" +
+		"\r\n" +
+		"7;a=1;b;c\r\nabcdefg\r\n" +
+		"0\r\n" +
+		"\r\n"
+	equals("Unmarshal", t, expected, string(req.Unmarshal()))
+}
@@ -0,0 +1,196 @@
+package http11p
+
+import (
+	"bytes"
+)
+
+// RFC9110 - 7.6.1. Connection
+//
+//  Connection        = #connection-option
+//  connection-option = token
+//
+// Connection options are case-insensitive.
+//
+
+// hasConnectionOption reports whether the Connection field in fieldLines
+// lists option.
+func hasConnectionOption(fieldLines FieldSection, option string) bool {
+	for _, member := range fieldLines.ListMembers("Connection") {
+		if matchesAll(member, NewTokenFinder()) && bytes.EqualFold(member, []byte(option)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RFC9112 - 9.3. Persistence
+//
+// A recipient determines whether a connection is persistent or not based on
+// the protocol version and Connection header field (Section 7.6.1 of
+// [HTTP]) in the most recently received message, if any:
+//
+//  - If the "close" connection option is present (Section 9.6), the
+//    connection will not persist after the current response; else,
+//  - If the received protocol is HTTP/1.1 (or later), the connection will
+//    persist after the current response; else,
+//  - If the received protocol is HTTP/1.0, the "keep-alive" connection
+//    option is present, either the recipient is not a proxy or the message
+//    is a response, and the recipient wishes to honor the HTTP/1.0
+//    "keep-alive" mechanism, the connection will persist after the current
+//    response; otherwise,
+//  - The connection will close after the current response.
+//
+// RFC9112 - 9.3. Persistence
+//
+// In order to remain persistent, all messages on a connection need to have
+// a self-defined message length (i.e., one not defined by closure of the
+// connection), as described in Section 6.
+//
+
+// IsPersistent reports whether the connection on which req was received and
+// resp was sent can be reused for the next request. It returns false if
+// either message closes the connection with the "close" option, is HTTP/1.0
+// without the "keep-alive" option, or has an unsupported HTTP-version, and
+// if resp has no self-defined length, switches protocols or starts a tunnel.
+//
+// NOTE
+// IsPersistent honors the "keep-alive" option of an HTTP/1.0 request, which
+// a proxy must not do.
+func IsPersistent(req Http11Request, resp Http11Response) bool {
+	if !isPersistentMessage(req.HttpVersion, req.FieldLines) {
+		return false
+	}
+	if !isPersistentMessage(resp.HttpVersion, resp.FieldLines) {
+		return false
+	}
+
+	code, ok := parseStatusCode(resp.StatusCode)
+	if !ok || code == StatusSwitchingProtocols {
+		return false
+	}
+	// The framing is determined again instead of using resp.CloseDelimited,
+	// which is set without the request method if resp was parsed with
+	// Marshal, e.g. for a response to HEAD.
+	framing, _, err := getResponseBodyFraming(req.Method, resp.HttpVersion, resp.StatusCode, resp.FieldLines)
+	if err != nil || framing == framingUntilClose || framing == framingTunnel {
+		return false
+	}
+
+	// The request-body must have been read to its end, which is not the case
+	// if its framing is invalid.
+	_, _, err = getBodyFraming(req.FieldLines, true)
+	return err == nil
+}
+
+// isPersistentMessage applies the rules of RFC9112 9.3 to a message.
+func isPersistentMessage(httpVersion []byte, fieldLines FieldSection) bool {
+	version, err := ParseHttpVersion(httpVersion)
+	if err != nil || !version.IsSupported() {
+		return false
+	}
+	if hasConnectionOption(fieldLines, "close") {
+		return false
+	}
+	if version.IsPersistentByDefault() {
+		return true
+	}
+	// The connection is closed after an HTTP/1.0 message with
+	// Transfer-Encoding, as checkTransferEncodingVersion describes.
+	if checkTransferEncodingVersion(httpVersion, fieldLines) != nil {
+		return false
+	}
+	return hasConnectionOption(fieldLines, "keep-alive")
+}
+
+// RFC9110 - 7.6.1. Connection
+//
+// Intermediaries MUST parse a received Connection header field before a
+// message is forwarded and, for each connection-option in this field,
+// remove any header or trailer field(s) from the message with the same name
+// as the connection-option, and then remove the Connection header field
+// itself (or replace it with the intermediary's own control options for the
+// forwarded message).
+//
+// Furthermore, intermediaries SHOULD remove or replace fields that are known
+// to require removal before forwarding, whether or not they appear as a
+// connection-option, after applying those fields' semantics. This includes
+// but is not limited to:
+//
+//  - Proxy-Connection (Appendix C.2.2 of [HTTP/1.1])
+//  - Keep-Alive (Section 19.7.1 of [RFC2068])
+//  - TE (Section 10.1.4)
+//  - Transfer-Encoding (Section 6.1 of [HTTP/1.1])
+//  - Upgrade (Section 7.8)
+//
+
+// hopByHopFieldNames is the field names which are removed before forwarding
+// whether or not they appear as a connection-option.
+var hopByHopFieldNames = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Connection",
+	"TE",
+	"Transfer-Encoding",
+	"Upgrade",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Trailer",
+}
+
+// hopByHopNames returns the names of the hop-by-hop fields of a message
+// whose header section is fieldSection.
+func hopByHopNames(fieldSection FieldSection) (names []string) {
+	names = append(names, hopByHopFieldNames...)
+	for _, member := range fieldSection.ListMembers("Connection") {
+		if matchesAll(member, NewTokenFinder()) {
+			names = append(names, string(member))
+		}
+	}
+	return
+}
+
+// delFieldNames removes all the field lines named one of names.
+func (fieldSection *FieldSection) delFieldNames(names []string) {
+	result := FieldSection{}
+	for _, fieldLine := range *fieldSection {
+		found := false
+		for _, name := range names {
+			if fieldNameEquals(fieldLine.FieldName, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, fieldLine)
+		}
+	}
+	*fieldSection = result
+}
+
+// DelHopByHop removes the hop-by-hop fields, which are the fields in
+// hopByHopFieldNames and the fields named by the options of the Connection
+// field.
+func (fieldSection *FieldSection) DelHopByHop() {
+	fieldSection.delFieldNames(hopByHopNames(*fieldSection))
+}
+
+// DelHopByHop removes the hop-by-hop fields from the header section and the
+// trailer section of req before it is forwarded. The Connection field in the
+// header section names the hop-by-hop fields of both sections.
+//
+// NOTE
+// Transfer-Encoding is removed while MessageBody stays decoded, so the
+// caller sets the framing of the forwarded message, e.g. Content-Length.
+func (req *Http11Request) DelHopByHop() {
+	names := hopByHopNames(req.FieldLines)
+	req.FieldLines.delFieldNames(names)
+	req.TrailerSection.delFieldNames(names)
+}
+
+// DelHopByHop removes the hop-by-hop fields from resp before it is
+// forwarded, like Http11Request.DelHopByHop.
+func (resp *Http11Response) DelHopByHop() {
+	names := hopByHopNames(resp.FieldLines)
+	resp.FieldLines.delFieldNames(names)
+	resp.TrailerSection.delFieldNames(names)
+}
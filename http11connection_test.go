@@ -0,0 +1,180 @@
+package http11p
+
+import (
+	"testing"
+)
+
+func TestIsPersistent(t *testing.T) {
+	tests := []struct {
+		testName string
+		req      []byte
+		resp     []byte
+		// If true, resp is parsed without the request method.
+		withoutMethod bool
+		expected      bool
+	}{
+		{
+			testName: "HTTP/1.1",
+			req:      []byte("GET / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"),
+			expected: true,
+		},
+		{
+			testName: "Connection: close in request",
+			req:      []byte("GET / HTTP/1.1\r\nHost: a\r\nConnection: Upgrade, Close\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"),
+			expected: false,
+		},
+		{
+			testName: "Connection: close in response",
+			req:      []byte("GET / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\nConnection: close\r\n\r\n"),
+			expected: false,
+		},
+		{
+			testName: "HTTP/1.0",
+			req:      []byte("GET / HTTP/1.0\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"),
+			expected: false,
+		},
+		{
+			testName: "HTTP/1.0 with keep-alive",
+			req:      []byte("GET / HTTP/1.0\r\nConnection: keep-alive\r\n\r\n"),
+			resp:     []byte("HTTP/1.0 200 OK\r\nContent-Length: 0\r\nConnection: Keep-Alive\r\n\r\n"),
+			expected: true,
+		},
+		{
+			testName: "HTTP/1.0 response without keep-alive",
+			req:      []byte("GET / HTTP/1.0\r\nConnection: keep-alive\r\n\r\n"),
+			resp:     []byte("HTTP/1.0 200 OK\r\nContent-Length: 0\r\n\r\n"),
+			expected: false,
+		},
+		{
+			testName: "HTTP/1.0 with Transfer-Encoding",
+			req:      []byte("GET / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			resp:     []byte("HTTP/1.0 200 OK\r\nTransfer-Encoding: chunked\r\nConnection: keep-alive\r\n\r\n0\r\n\r\n"),
+			expected: false,
+		},
+		{
+			testName: "close-delimited message-body",
+			req:      []byte("GET / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 200 OK\r\n\r\nabc"),
+			expected: false,
+		},
+		{
+			testName: "response to HEAD without Content-Length",
+			req:      []byte("HEAD / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 200 OK\r\n\r\n"),
+			expected: true,
+		},
+		{
+			testName:      "response to HEAD parsed without the method",
+			req:           []byte("HEAD / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			resp:          []byte("HTTP/1.1 200 OK\r\n\r\n"),
+			withoutMethod: true,
+			expected:      true,
+		},
+		{
+			testName: "204",
+			req:      []byte("DELETE / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 204 No Content\r\n\r\n"),
+			expected: true,
+		},
+		{
+			testName: "101",
+			req:      []byte("GET / HTTP/1.1\r\nHost: a\r\nConnection: upgrade\r\nUpgrade: websocket\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 101 Switching Protocols\r\nConnection: upgrade\r\nUpgrade: websocket\r\n\r\n"),
+			expected: false,
+		},
+		{
+			testName: "2xx to CONNECT",
+			req:      []byte("CONNECT a:443 HTTP/1.1\r\nHost: a:443\r\n\r\n"),
+			resp:     []byte("HTTP/1.1 200 OK\r\n\r\n"),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			var req Http11Request
+			err := req.Marshal(test.req)
+			if err != nil {
+				t.Errorf("Failed to marshal Http/1.1 Request: %v", err.Error())
+				return
+			}
+			method := req.Method
+			if test.withoutMethod {
+				method = nil
+			}
+			var resp Http11Response
+			_, err = resp.MarshalForRequest(test.resp, method)
+			if err != nil {
+				t.Errorf("Failed to marshal Http/1.1 Response: %v", err.Error())
+				return
+			}
+			equals(test.testName, t, test.expected, IsPersistent(req, resp))
+		})
+	}
+}
+
+func TestIsPersistentWithBuiltResponse(t *testing.T) {
+	req := Http11Request{
+		Method:        []byte("GET"),
+		RequestTarget: []byte("/"),
+		HttpVersion:   []byte("HTTP/1.1"),
+	}
+	resp := NewHttp11Response(StatusOk)
+	equals("without Content-Length", t, false, IsPersistent(req, resp))
+
+	resp.FieldLines.Add("Content-Length", []byte("0"))
+	equals("with Content-Length", t, true, IsPersistent(req, resp))
+}
+
+func TestHttp11RequestDelHopByHop(t *testing.T) {
+	var req Http11Request
+	err := req.Marshal([]byte(
+		"POST / HTTP/1.1\r\n" +
+			"Host: a\r\n" +
+			"Connection: keep-alive, X-Hop\r\n" +
+			"connection: \"quoted\", , x-other\r\n" +
+			"Keep-Alive: timeout=5\r\n" +
+			"x-hop: 1\r\n" +
+			"X-Other: 2\r\n" +
+			"X-End: 3\r\n" +
+			"Proxy-Authorization: Basic YTpi\r\n" +
+			"TE: trailers\r\n" +
+			"Trailer: X-Hop, X-Checksum\r\n" +
+			"Transfer-Encoding: chunked\r\n" +
+			"\r\n" +
+			"3\r\nabc\r\n0\r\nX-Hop: 4\r\nX-Checksum: 5\r\n\r\n",
+	))
+	if err != nil {
+		t.Errorf("Failed to marshal Http/1.1 Request: %v", err.Error())
+		return
+	}
+
+	req.DelHopByHop()
+	expectedFieldLines := FieldSection{
+		{FieldName: []byte("Host"), FieldValue: []byte("a")},
+		{FieldName: []byte("X-End"), FieldValue: []byte("3")},
+	}
+	equals("len(FieldLines)", t, len(expectedFieldLines), len(req.FieldLines))
+	for i := 0; i < len(expectedFieldLines) && i < len(req.FieldLines); i++ {
+		equals("FieldName", t, string(expectedFieldLines[i].FieldName), string(req.FieldLines[i].FieldName))
+		equals("FieldValue", t, string(expectedFieldLines[i].FieldValue), string(req.FieldLines[i].FieldValue))
+	}
+	equals("len(TrailerSection)", t, 1, len(req.TrailerSection))
+	equals("TrailerSection", t, "5", string(req.TrailerSection.Get("X-Checksum")))
+}
+
+func TestFieldSectionDelHopByHop(t *testing.T) {
+	fieldLines := FieldSection{
+		{FieldName: []byte("Upgrade"), FieldValue: []byte("websocket")},
+		{FieldName: []byte("Proxy-Connection"), FieldValue: []byte("keep-alive")},
+		{FieldName: []byte("Proxy-Authenticate"), FieldValue: []byte("Basic")},
+		{FieldName: []byte("Content-Length"), FieldValue: []byte("0")},
+	}
+	fieldLines.DelHopByHop()
+	equals("len(FieldLines)", t, 1, len(fieldLines))
+	equals("Has(Content-Length)", t, true, fieldLines.Has("Content-Length"))
+}
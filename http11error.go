@@ -0,0 +1,273 @@
+package http11p
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// Sentinel errors wrapped by ParseError. Use errors.Is to classify an error
+// returned by the parsers.
+var (
+	ErrInvalidRequestLine   = errors.New("invalid request-line")
+	ErrInvalidRequestTarget = errors.New("invalid request-target")
+	ErrInvalidStatusLine    = errors.New("invalid status-line")
+	ErrInvalidHttpVersion   = errors.New("invalid HTTP-version")
+	ErrInvalidFieldLine     = errors.New("invalid field-line")
+	ErrInvalidContentLength = errors.New("invalid Content-Length")
+	ErrInvalidChunkedBody   = errors.New("invalid chunked-body")
+	ErrObsFold              = errors.New("obs-fold in field-value")
+	ErrEmptyAuthority       = errors.New("empty authority in target URI")
+	ErrInvalidVia           = errors.New("invalid Via")
+	ErrInvalidForwarded     = errors.New("invalid Forwarded")
+
+	// Violations of the message framing rules, which can cause request
+	// smuggling when the recipients of a message disagree on its length.
+	ErrWhitespaceBeforeColon             = errors.New("whitespace between field-name and colon")
+	ErrConflictingContentLength          = errors.New("conflicting Content-Length values")
+	ErrContentLengthWithTransferEncoding = errors.New("Content-Length with Transfer-Encoding")
+	ErrChunkedNotFinal                   = errors.New("chunked is not the final transfer coding")
+	ErrObfuscatedTransferEncoding        = errors.New("obfuscated Transfer-Encoding")
+	ErrTransferEncodingInHttp10          = errors.New("Transfer-Encoding in HTTP/1.0")
+
+	// Violations of the rules for the Host field.
+	ErrMissingHost   = errors.New("Host not found")
+	ErrDuplicateHost = errors.New("more than one Host")
+	ErrInvalidHost   = errors.New("invalid Host")
+	ErrHostMismatch  = errors.New("Host differs from authority of request-target")
+
+	// ErrIncompleteMessage is returned by Marshal when data ends before the
+	// end of the message, e.g. in the middle of the header section or before
+	// the end of the message-body.
+	ErrIncompleteMessage = errors.New("incomplete message")
+
+	// ErrUnsupportedHttpVersion is returned when the major version of
+	// HTTP-version is not 1.
+	ErrUnsupportedHttpVersion = errors.New("unsupported HTTP-version")
+
+	// Violations of the limits of ParserOptions.
+	ErrStartLineTooLong      = errors.New("start-line too long")
+	ErrFieldLineTooLong      = errors.New("field-line too long")
+	ErrTooManyFieldLines     = errors.New("too many field lines")
+	ErrHeaderSectionTooLarge = errors.New("field section too large")
+	ErrBodyTooLarge          = errors.New("message-body too large")
+	ErrChunkSizeLineTooLong  = errors.New("chunk-size line too long")
+)
+
+// StatusCodeForError returns the status code of the response which a server
+// sends when it fails to parse a request with err: 501 for
+// ErrStartLineTooLong at the method, 414 for the other ErrStartLineTooLong,
+// 431 for ErrFieldLineTooLong, ErrTooManyFieldLines and
+// ErrHeaderSectionTooLarge, 413 for ErrBodyTooLarge, 505 for
+// ErrUnsupportedHttpVersion, and 400 for the other ParseErrors. It returns 0
+// if err is not a ParseError.
+func StatusCodeForError(err error) int {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		return 0
+	}
+	switch parseErr.Err {
+	case ErrStartLineTooLong:
+		if parseErr.Rule == "method" {
+			return int(StatusNotImplemented)
+		}
+		return int(StatusUriTooLong)
+	case ErrFieldLineTooLong, ErrTooManyFieldLines, ErrHeaderSectionTooLarge:
+		return int(StatusRequestHeaderFieldsTooLarge)
+	case ErrBodyTooLarge:
+		return int(StatusContentTooLarge)
+	case ErrUnsupportedHttpVersion:
+		return int(StatusHttpVersionNotSupported)
+	}
+	return int(StatusBadRequest)
+}
+
+// excerptLength is the maximum length of ParseError.Excerpt.
+const excerptLength = 16
+
+// ParseError describes where and why a message could not be parsed.
+type ParseError struct {
+	// Err is one of the sentinel errors above.
+	Err error
+
+	// Rule is the name of the grammar rule which failed, e.g. "field-name".
+	Rule string
+
+	// Offset is the byte offset of the failure from the start of the
+	// message. Line and Column are 1-based, and Column counts bytes. Line is
+	// 0 if the error is not located in the input, e.g. one returned by
+	// Validate.
+	Offset int
+	Line   int
+	Column int
+
+	// Excerpt is the input starting at Offset, up to 16 bytes.
+	Excerpt []byte
+
+	message string
+
+	// rest is the input starting at the failure, which is used to locate
+	// the failure in the whole message.
+	rest []byte
+
+	// fieldLine is the index of the field line which caused the failure
+	// plus 1, or 0. It is used to locate the failures found after parsing
+	// the header section, e.g. an invalid Content-Length.
+	fieldLine int
+}
+
+func newParseError(err error, rule string, message string, rest []byte) *ParseError {
+	return &ParseError{Err: err, Rule: rule, message: message, rest: rest}
+}
+
+// newFieldLineError returns a ParseError caused by the i-th field line of
+// the header section, which is located by locateFieldLineError.
+func newFieldLineError(err error, rule string, message string, i int) *ParseError {
+	parseErr := newParseError(err, rule, message, nil)
+	parseErr.fieldLine = i + 1
+	return parseErr
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return e.message
+	}
+	return fmt.Sprintf("%s at line %d, column %d: %q", e.message, e.Line, e.Column, e.Excerpt)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// position is a location in a message. line and column are 1-based.
+type position struct {
+	offset int
+	line   int
+	column int
+}
+
+var startPosition = position{offset: 0, line: 1, column: 1}
+
+// advance returns the position after data, which starts at pos.
+func (pos position) advance(data []byte) position {
+	for _, b := range data {
+		pos.offset++
+		pos.column++
+		if b == '\n' {
+			pos.line++
+			pos.column = 1
+		}
+	}
+	return pos
+}
+
+// withRest sets the failure of err to the start of rest, unless err already
+// knows where it failed.
+func withRest(err error, rest []byte) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && parseErr.rest == nil {
+		parseErr.rest = rest
+	}
+	return err
+}
+
+// locateError sets the position of err in input, the message which starts
+// at the beginning of the data.
+func locateError(err error, input []byte) error {
+	return locateErrorAt(err, startPosition, input)
+}
+
+// locateErrorAt sets the position of err in input, a part of the message
+// which starts at pos. The rest of err must be a suffix of input.
+func locateErrorAt(err error, pos position, input []byte) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || len(parseErr.rest) > len(input) {
+		return err
+	}
+	pos = pos.advance(input[:len(input)-len(parseErr.rest)])
+	parseErr.Offset = pos.offset
+	parseErr.Line = pos.line
+	parseErr.Column = pos.column
+	excerpt := parseErr.rest
+	if len(excerpt) > excerptLength {
+		excerpt = excerpt[:excerptLength]
+	}
+	parseErr.Excerpt = append([]byte{}, excerpt...)
+	return err
+}
+
+// orIncomplete returns err as ErrIncompleteMessage if err is a syntax
+// error in line, the last line of the data, and line is the part of a valid
+// lineRule received so far. isPrefix reports whether data is the part
+// received so far of what finder matches. The parsers of whole messages use
+// it to tell a message which is cut short from a malformed one.
+func orIncomplete(
+	err error,
+	lineRule string,
+	line []byte,
+	finder abnfp.Finder,
+	isPrefix func(data []byte) bool,
+) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || bytes.IndexByte(line, '\n') >= 0 {
+		return err
+	}
+	switch parseErr.Err {
+	case ErrInvalidRequestLine, ErrInvalidStatusLine, ErrInvalidFieldLine, ErrInvalidChunkedBody:
+	default:
+		return err
+	}
+	if bytes.HasSuffix(line, []byte("\r")) {
+		if !matchesAll(line[:len(line)-1], finder) {
+			return err
+		}
+	} else if !isPrefix(line) {
+		return err
+	}
+	parseErr.Err = ErrIncompleteMessage
+	parseErr.message = "message ends before the end of the " + lineRule
+	return err
+}
+
+// fieldLineStart is where a field line starts in a message. rest is the
+// input starting at the field line, which contains at least the field line.
+type fieldLineStart struct {
+	pos  position
+	rest []byte
+}
+
+// scanFieldLineStarts returns the starts of the field lines in data, the
+// header section which starts at pos, skipping the lines which continue a
+// field line with obs-fold.
+func scanFieldLineStarts(data []byte, pos position, options ParserOptions) (starts []fieldLineStart) {
+	for len(data) > 0 {
+		crlf, _ := abnfp.Parse(data, options.newEolFinder())
+		if len(crlf) > 0 {
+			break
+		}
+		if !isContinuationLine(data) {
+			starts = append(starts, fieldLineStart{pos: pos, rest: data})
+		}
+		n := lineLength(data)
+		pos = pos.advance(data[:n])
+		data = data[n:]
+	}
+	return
+}
+
+// locateFieldLineError sets the position of err, which is caused by a field
+// line, to the field-value of the field line in starts.
+func locateFieldLineError(err error, starts []fieldLineStart) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.fieldLine < 1 || parseErr.fieldLine > len(starts) {
+		return err
+	}
+	start := starts[parseErr.fieldLine-1]
+	_, rest := abnfp.Parse(start.rest, NewFieldNameFinder())
+	_, rest = abnfp.Parse(rest, abnfp.NewByteFinder(':'))
+	_, rest = abnfp.Parse(rest, NewOwsFinder())
+	parseErr.rest = rest
+	return locateErrorAt(err, start.pos, start.rest)
+}
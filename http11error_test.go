@@ -0,0 +1,516 @@
+package http11p
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type TestCaseForParseError struct {
+	testName       string
+	data           []byte
+	expectedErr    error
+	expectedRule   string
+	expectedOffset int
+	expectedLine   int
+	expectedColumn int
+}
+
+func execTestForParseError(testName string, t *testing.T, test TestCaseForParseError, err error) {
+	if !errors.Is(err, test.expectedErr) {
+		t.Errorf("%v: expected: %v, actual: %v", testName, test.expectedErr, err)
+		return
+	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("%v: expected: *ParseError, actual: %T", testName, err)
+		return
+	}
+	equals(testName+" Rule", t, test.expectedRule, parseErr.Rule)
+	equals(testName+" Offset", t, test.expectedOffset, parseErr.Offset)
+	equals(testName+" Line", t, test.expectedLine, parseErr.Line)
+	equals(testName+" Column", t, test.expectedColumn, parseErr.Column)
+	// The streaming parsers do not see the data after the current line.
+	if !bytes.HasPrefix(test.data[test.expectedOffset:], parseErr.Excerpt) {
+		t.Errorf("%v Excerpt: expected: prefix of %q, actual: %q", testName, test.data[test.expectedOffset:], parseErr.Excerpt)
+	}
+}
+
+func TestRequestParseError(t *testing.T) {
+	tests := []TestCaseForParseError{
+		{
+			testName:       "no method",
+			data:           []byte(" / HTTP/1.1\r\n\r\n"),
+			expectedErr:    ErrInvalidRequestLine,
+			expectedRule:   "method",
+			expectedOffset: 0,
+			expectedLine:   1,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "invalid http-version",
+			data:           []byte("GET / HTTP/11\r\n\r\n"),
+			expectedErr:    ErrInvalidRequestLine,
+			expectedRule:   "HTTP-version",
+			expectedOffset: 6,
+			expectedLine:   1,
+			expectedColumn: 7,
+		},
+		{
+			testName:       "request-target is not absolute-URI",
+			data:           []byte("GET ab HTTP/1.1\r\n\r\n"),
+			expectedErr:    ErrInvalidRequestLine,
+			expectedRule:   "request-target",
+			expectedOffset: 4,
+			expectedLine:   1,
+			expectedColumn: 5,
+		},
+		{
+			testName:       "SP in method",
+			data:           []byte("P ST / HTTP/1.1\r\n\r\n"),
+			expectedErr:    ErrInvalidRequestLine,
+			expectedRule:   "request-target",
+			expectedOffset: 2,
+			expectedLine:   1,
+			expectedColumn: 3,
+		},
+		{
+			testName:       "unsupported http-version",
+			data:           []byte("GET / HTTP/2.0\r\n\r\n"),
+			expectedErr:    ErrUnsupportedHttpVersion,
+			expectedRule:   "HTTP-version",
+			expectedOffset: 6,
+			expectedLine:   1,
+			expectedColumn: 7,
+		},
+		{
+			testName:       "no colon after field-name",
+			data:           []byte("GET / HTTP/1.1\r\nHost: a\r\nAccept text/html\r\n\r\n"),
+			expectedErr:    ErrInvalidFieldLine,
+			expectedRule:   "field-line",
+			expectedOffset: 25,
+			expectedLine:   3,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "no colon after field-name in trailer-section",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n0\r\nExpires 0\r\n\r\n"),
+			expectedErr:    ErrInvalidFieldLine,
+			expectedRule:   "field-line",
+			expectedOffset: 50,
+			expectedLine:   5,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "whitespace before colon",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding : chunked\r\n\r\n"),
+			expectedErr:    ErrWhitespaceBeforeColon,
+			expectedRule:   "field-name",
+			expectedOffset: 34,
+			expectedLine:   2,
+			expectedColumn: 18,
+		},
+		{
+			testName:       "chunked is not final",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked, gzip\r\n\r\n"),
+			expectedErr:    ErrChunkedNotFinal,
+			expectedRule:   "Transfer-Encoding",
+			expectedOffset: 36,
+			expectedLine:   2,
+			expectedColumn: 20,
+		},
+		{
+			testName:       "Transfer-Encoding in HTTP/1.0",
+			data:           []byte("POST / HTTP/1.0\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n"),
+			expectedErr:    ErrTransferEncodingInHttp10,
+			expectedRule:   "Transfer-Encoding",
+			expectedOffset: 36,
+			expectedLine:   2,
+			expectedColumn: 20,
+		},
+		{
+			testName:       "conflicting Content-Length",
+			data:           []byte("POST / HTTP/1.1\r\nContent-Length: 1, 2\r\n\r\nab"),
+			expectedErr:    ErrConflictingContentLength,
+			expectedRule:   "Content-Length",
+			expectedOffset: 33,
+			expectedLine:   2,
+			expectedColumn: 17,
+		},
+		{
+			testName:       "conflicting Content-Length in two field lines",
+			data:           []byte("POST / HTTP/1.1\r\nContent-Length: 1\r\nHost: a\r\nContent-Length:  2\r\n\r\nab"),
+			expectedErr:    ErrConflictingContentLength,
+			expectedRule:   "Content-Length",
+			expectedOffset: 62,
+			expectedLine:   4,
+			expectedColumn: 18,
+		},
+		{
+			testName:       "invalid Content-Length",
+			data:           []byte("POST / HTTP/1.1\r\nHost: a\r\nContent-Length: x\r\n\r\n"),
+			expectedErr:    ErrInvalidContentLength,
+			expectedRule:   "Content-Length",
+			expectedOffset: 42,
+			expectedLine:   3,
+			expectedColumn: 17,
+		},
+		{
+			testName:       "invalid chunk-size",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc\r\nx\r\n"),
+			expectedErr:    ErrInvalidChunkedBody,
+			expectedRule:   "chunk-size",
+			expectedOffset: 55,
+			expectedLine:   6,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "no CRLF after chunk-data",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabcd\r\n0\r\n\r\n"),
+			expectedErr:    ErrInvalidChunkedBody,
+			expectedRule:   "CRLF",
+			expectedOffset: 53,
+			expectedLine:   5,
+			expectedColumn: 4,
+		},
+	}
+
+	for _, test := range tests {
+		var req Http11Request
+		err := req.Marshal(test.data)
+		execTestForParseError(test.testName+" (Marshal)", t, test, err)
+
+		p := NewRequestParser()
+		err = p.Feed(test.data)
+		execTestForParseError(test.testName+" (RequestParser)", t, test, err)
+
+		reader := bufio.NewReader(bytes.NewReader(test.data))
+		readReq, err := ReadRequest(reader)
+		if err == nil {
+			_, err = readReq.Body.Read(make([]byte, len(test.data)))
+			for err == nil {
+				_, err = readReq.Body.Read(make([]byte, len(test.data)))
+			}
+		}
+		execTestForParseError(test.testName+" (ReadRequest)", t, test, err)
+	}
+}
+
+func TestResponseParseError(t *testing.T) {
+	tests := []TestCaseForParseError{
+		{
+			testName:       "invalid status-code",
+			data:           []byte("HTTP/1.1 2000 OK\r\n\r\n"),
+			expectedErr:    ErrInvalidStatusLine,
+			expectedRule:   "SP",
+			expectedOffset: 12,
+			expectedLine:   1,
+			expectedColumn: 13,
+		},
+		{
+			testName:       "unsupported http-version",
+			data:           []byte("HTTP/0.9 200 OK\r\n\r\n"),
+			expectedErr:    ErrUnsupportedHttpVersion,
+			expectedRule:   "HTTP-version",
+			expectedOffset: 0,
+			expectedLine:   1,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "invalid Content-Length",
+			data:           []byte("HTTP/1.1 200 OK\r\nContent-Length: 1x\r\n\r\n"),
+			expectedErr:    ErrInvalidContentLength,
+			expectedRule:   "Content-Length",
+			expectedOffset: 33,
+			expectedLine:   2,
+			expectedColumn: 17,
+		},
+		{
+			testName:       "message-body shorter than Content-Length",
+			data:           []byte("HTTP/1.1 200 OK\r\nContent-Length: 10\r\n\r\nabc"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "message-body",
+			expectedOffset: 42,
+			expectedLine:   4,
+			expectedColumn: 4,
+		},
+	}
+
+	for _, test := range tests {
+		var resp Http11Response
+		err := resp.Marshal(test.data)
+		execTestForParseError(test.testName, t, test, err)
+	}
+
+	// The errors found after the header section are located in the
+	// streaming parsers as well.
+	test := tests[len(tests)-2]
+	err := NewResponseParser().Feed(test.data)
+	execTestForParseError(test.testName+" (ResponseParser)", t, test, err)
+	_, err = ReadResponse(bufio.NewReader(bytes.NewReader(test.data)))
+	execTestForParseError(test.testName+" (ReadResponse)", t, test, err)
+}
+
+// Marshal reports the data which ends before the end of the message with
+// ErrIncompleteMessage, wherever the data ends, and the data which is
+// malformed before its end with the other errors.
+func TestMarshalIncompleteMessage(t *testing.T) {
+	requestTests := []TestCaseForParseError{
+		{
+			testName:       "ends in method",
+			data:           []byte("GE"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "SP",
+			expectedOffset: 2,
+			expectedLine:   1,
+			expectedColumn: 3,
+		},
+		{
+			testName:       "ends in request-target",
+			data:           []byte("GET /inde"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "request-target",
+			expectedOffset: 4,
+			expectedLine:   1,
+			expectedColumn: 5,
+		},
+		{
+			testName:       "ends in HTTP-version",
+			data:           []byte("GET / HTT"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "HTTP-version",
+			expectedOffset: 6,
+			expectedLine:   1,
+			expectedColumn: 7,
+		},
+		{
+			testName:       "ends before CRLF after request-line",
+			data:           []byte("GET / HTTP/1.1\r"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 14,
+			expectedLine:   1,
+			expectedColumn: 15,
+		},
+		{
+			testName:       "ends in field-name",
+			data:           []byte("GET / HTTP/1.1\r\nHo"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "field-line",
+			expectedOffset: 16,
+			expectedLine:   2,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "ends in field-value",
+			data:           []byte("GET / HTTP/1.1\r\nHost: a"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 23,
+			expectedLine:   2,
+			expectedColumn: 8,
+		},
+		{
+			testName:       "ends before empty line",
+			data:           []byte("GET / HTTP/1.1\r\nHost: a\r\n"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 25,
+			expectedLine:   3,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "ends in empty line",
+			data:           []byte("GET / HTTP/1.1\r\nHost: a\r\n\r"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 25,
+			expectedLine:   3,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "ends in chunk-size line",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3;a"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 50,
+			expectedLine:   4,
+			expectedColumn: 4,
+		},
+		{
+			testName:       "ends before CRLF after chunk-data",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 53,
+			expectedLine:   5,
+			expectedColumn: 4,
+		},
+		{
+			testName:       "ends before chunk-size",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc\r\n"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "chunk-size",
+			expectedOffset: 55,
+			expectedLine:   6,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "ends in trailer-section",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n0\r\nExpi"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "field-line",
+			expectedOffset: 50,
+			expectedLine:   5,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "ends before CRLF after trailer-section",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 50,
+			expectedLine:   5,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "invalid HTTP-version at end of data",
+			data:           []byte("GET / HTTX"),
+			expectedErr:    ErrInvalidRequestLine,
+			expectedRule:   "HTTP-version",
+			expectedOffset: 6,
+			expectedLine:   1,
+			expectedColumn: 7,
+		},
+		{
+			testName:       "invalid field-name at end of data",
+			data:           []byte("GET / HTTP/1.1\r\nHo@"),
+			expectedErr:    ErrInvalidFieldLine,
+			expectedRule:   "field-line",
+			expectedOffset: 16,
+			expectedLine:   2,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "invalid chunk-size at end of data",
+			data:           []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\nx"),
+			expectedErr:    ErrInvalidChunkedBody,
+			expectedRule:   "chunk-size",
+			expectedOffset: 47,
+			expectedLine:   4,
+			expectedColumn: 1,
+		},
+	}
+	for _, test := range requestTests {
+		var req Http11Request
+		err := req.Marshal(test.data)
+		execTestForParseError(test.testName+" (request)", t, test, err)
+	}
+
+	responseTests := []TestCaseForParseError{
+		{
+			testName:       "ends in HTTP-version",
+			data:           []byte("HTTP/1"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "HTTP-version",
+			expectedOffset: 0,
+			expectedLine:   1,
+			expectedColumn: 1,
+		},
+		{
+			testName:       "ends in status-code",
+			data:           []byte("HTTP/1.1 20"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "status-code",
+			expectedOffset: 9,
+			expectedLine:   1,
+			expectedColumn: 10,
+		},
+		{
+			testName:       "ends in reason-phrase",
+			data:           []byte("HTTP/1.1 200 O"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 14,
+			expectedLine:   1,
+			expectedColumn: 15,
+		},
+		{
+			testName:       "ends in field-value",
+			data:           []byte("HTTP/1.1 200 OK\r\nContent-Length: 1"),
+			expectedErr:    ErrIncompleteMessage,
+			expectedRule:   "CRLF",
+			expectedOffset: 34,
+			expectedLine:   2,
+			expectedColumn: 18,
+		},
+		{
+			testName:       "invalid status-code at end of data",
+			data:           []byte("HTTP/1.1 2x"),
+			expectedErr:    ErrInvalidStatusLine,
+			expectedRule:   "status-code",
+			expectedOffset: 9,
+			expectedLine:   1,
+			expectedColumn: 10,
+		},
+	}
+	for _, test := range responseTests {
+		var resp Http11Response
+		err := resp.Marshal(test.data)
+		execTestForParseError(test.testName+" (response)", t, test, err)
+	}
+}
+
+func TestMarshalRequestsParseError(t *testing.T) {
+	data := []byte("GET / HTTP/1.1\r\n\r\nGET / HTTP/1.1\r\nHost\r\n\r\n")
+	_, _, err := MarshalRequests(data)
+	execTestForParseError("MarshalRequests", t, TestCaseForParseError{
+		data:           data,
+		expectedErr:    ErrInvalidFieldLine,
+		expectedRule:   "field-line",
+		expectedOffset: 34,
+		expectedLine:   4,
+		expectedColumn: 1,
+	}, err)
+}
+
+func TestParseErrorError(t *testing.T) {
+	var req Http11Request
+	err := req.Marshal([]byte("GET / HTTP/1.1\r\nHost: a\r\nAccept text/html\r\n\r\n"))
+	equals(
+		"Error",
+		t,
+		"invalid field-line at line 3, column 1: \"Accept text/html\"",
+		err.Error(),
+	)
+}
+
+func TestIncompleteMessageError(t *testing.T) {
+	tests := []struct {
+		testName string
+		data     []byte
+		expected string
+	}{
+		{
+			testName: "start-line",
+			data:     []byte("GET / HTT"),
+			expected: "message ends before the end of the request-line at line 1, column 7: \"HTT\"",
+		},
+		{
+			testName: "field-line",
+			data:     []byte("GET / HTTP/1.1\r\nHo"),
+			expected: "message ends before the end of the field-line at line 2, column 1: \"Ho\"",
+		},
+		{
+			testName: "chunk-size line",
+			data:     []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3;a"),
+			expected: "message ends before the end of the chunk-size line at line 4, column 4: \"\"",
+		},
+	}
+	for _, test := range tests {
+		var req Http11Request
+		err := req.Marshal(test.data)
+		equals(test.testName+" Error", t, test.expected, err.Error())
+	}
+}
@@ -0,0 +1,284 @@
+package http11p
+
+import (
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC9110 - 5.2. Field Lines and Combined Field Value
+//
+// Field sections are composed of any number of "field lines", each with a
+// "field name" (see Section 5.1) identifying the field, and a "field line
+// value" that conveys data for that instance of the field.
+//
+// RFC9110 - 5.1. Field Names
+//
+// Field names are case-insensitive.
+//
+
+// FieldSection is the list of field lines of a header section or a trailer
+// section, in the order they were received.
+type FieldSection []FieldLine
+
+// fieldNameEquals compares field names ignoring ASCII case. field-name is a
+// token, so other bytes never match case-insensitively.
+func fieldNameEquals(fieldName []byte, name string) bool {
+	if len(fieldName) != len(name) {
+		return false
+	}
+	for i := 0; i < len(fieldName); i++ {
+		if toLowerAscii(fieldName[i]) != toLowerAscii(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func toLowerAscii(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + ('a' - 'A')
+	}
+	return b
+}
+
+// Get returns the value of the first field line named name, or nil if there
+// is none.
+func (fieldSection FieldSection) Get(name string) []byte {
+	for _, fieldLine := range fieldSection {
+		if fieldNameEquals(fieldLine.FieldName, name) {
+			return fieldLine.FieldValue
+		}
+	}
+	return nil
+}
+
+// Values returns the values of all the field lines named name, in order.
+func (fieldSection FieldSection) Values(name string) (values [][]byte) {
+	for _, fieldLine := range fieldSection {
+		if fieldNameEquals(fieldLine.FieldName, name) {
+			values = append(values, fieldLine.FieldValue)
+		}
+	}
+	return
+}
+
+// lastFieldLine returns the index of the last field line named name, or -1
+// if there is none.
+func lastFieldLine(fieldSection FieldSection, name string) int {
+	for i := len(fieldSection) - 1; i >= 0; i-- {
+		if fieldNameEquals(fieldSection[i].FieldName, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Has reports whether there is a field line named name.
+func (fieldSection FieldSection) Has(name string) bool {
+	for _, fieldLine := range fieldSection {
+		if fieldNameEquals(fieldLine.FieldName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add appends a field line.
+func (fieldSection *FieldSection) Add(name string, value []byte) {
+	*fieldSection = append(
+		*fieldSection,
+		FieldLine{FieldName: []byte(name), FieldValue: value},
+	)
+}
+
+// Set replaces the value of the first field line named name and removes the
+// others. If there is none, it appends a field line.
+func (fieldSection *FieldSection) Set(name string, value []byte) {
+	result := FieldSection{}
+	set := false
+	for _, fieldLine := range *fieldSection {
+		if !fieldNameEquals(fieldLine.FieldName, name) {
+			result = append(result, fieldLine)
+			continue
+		}
+		if set {
+			continue
+		}
+		result = append(result, FieldLine{FieldName: fieldLine.FieldName, FieldValue: value})
+		set = true
+	}
+	*fieldSection = result
+	if !set {
+		fieldSection.Add(name, value)
+	}
+}
+
+// Del removes all the field lines named name.
+func (fieldSection *FieldSection) Del(name string) {
+	result := FieldSection{}
+	for _, fieldLine := range *fieldSection {
+		if !fieldNameEquals(fieldLine.FieldName, name) {
+			result = append(result, fieldLine)
+		}
+	}
+	*fieldSection = result
+}
+
+// Range calls f for each field line in order until f returns false.
+func (fieldSection FieldSection) Range(f func(fieldName []byte, fieldValue []byte) bool) {
+	for _, fieldLine := range fieldSection {
+		if !f(fieldLine.FieldName, fieldLine.FieldValue) {
+			return
+		}
+	}
+}
+
+// RFC9110 - 5.6.1.2. Recipient Requirements
+//
+// Empty elements do not contribute to the count of elements present. A
+// recipient MUST parse and ignore a reasonable number of empty list
+// elements.
+//
+// RFC9110 - 5.3. Field Order
+//
+// A recipient MAY combine multiple field lines within a field section that
+// have the same field name into one field line, without changing the
+// semantics of the message, by appending each subsequent field line value
+// to the initial field line value in order, separated by a comma (",") and
+// optional whitespace (OWS, defined in Section 5.6.3).
+//
+
+// ListMembers returns the members of the comma-separated list formed by all
+// the field lines named name. Commas in quoted-strings do not separate
+// members, and empty members are omitted.
+func (fieldSection FieldSection) ListMembers(name string) (members [][]byte) {
+	members = [][]byte{}
+	for _, fieldValue := range fieldSection.Values(name) {
+		members = append(members, splitListMembers(fieldValue)...)
+	}
+	return
+}
+
+func splitListMembers(fieldValue []byte) (members [][]byte) {
+	members = [][]byte{}
+	start := 0
+	i := 0
+	for i <= len(fieldValue) {
+		if i < len(fieldValue) && fieldValue[i] == '"' {
+			quotedString, _ := abnfp.Parse(fieldValue[i:], NewQuotedStringFinder())
+			if len(quotedString) > 0 {
+				i += len(quotedString)
+				continue
+			}
+		}
+		if i == len(fieldValue) || fieldValue[i] == ',' {
+			member := trimOws(fieldValue[start:i])
+			if len(member) > 0 {
+				members = append(members, member)
+			}
+			start = i + 1
+		}
+		i++
+	}
+	return
+}
+
+func trimOws(data []byte) []byte {
+	_, data = abnfp.Parse(data, NewOwsFinder())
+	end := len(data)
+	for end > 0 && (data[end-1] == ' ' || data[end-1] == '\t') {
+		end--
+	}
+	return data[:end]
+}
+
+// listElements returns the elements of the lists formed by the field lines
+// named name, which are parsed with element so that delimiters in a
+// quoted-string or a comment do not separate elements. It returns false if
+// a field line is not such a list.
+func (fieldSection FieldSection) listElements(name string, element abnfp.Finder) (elements [][]byte, ok bool) {
+	elements = [][]byte{}
+	for _, fieldValue := range fieldSection.Values(name) {
+		remaining := fieldValue
+		for {
+			_, remaining = abnfp.Parse(remaining, NewOwsFinder())
+			if len(remaining) == 0 {
+				break
+			}
+			if remaining[0] == ',' {
+				remaining = remaining[1:]
+				continue
+			}
+			var parsed []byte
+			parsed, remaining = abnfp.Parse(remaining, element.Copy())
+			if len(parsed) == 0 {
+				return elements, false
+			}
+			elements = append(elements, parsed)
+			_, remaining = abnfp.Parse(remaining, NewOwsFinder())
+			if len(remaining) > 0 && remaining[0] != ',' {
+				return elements, false
+			}
+		}
+	}
+	return elements, true
+}
+
+// addListMember appends member to the list formed by the field lines named
+// name. The member is appended to the value of the last field line so that
+// the order of the list is kept.
+func (fieldSection *FieldSection) addListMember(name string, member []byte) {
+	for i := len(*fieldSection) - 1; i >= 0; i-- {
+		fieldLine := &(*fieldSection)[i]
+		if !fieldNameEquals(fieldLine.FieldName, name) {
+			continue
+		}
+		fieldValue := trimOws(fieldLine.FieldValue)
+		value := make([]byte, 0, len(fieldValue)+2+len(member))
+		value = append(value, fieldValue...)
+		if len(value) > 0 {
+			value = append(value, ", "...)
+		}
+		value = append(value, member...)
+		fieldLine.FieldValue = value
+		return
+	}
+	fieldSection.Add(name, member)
+}
+
+// RFC9110 - 5.6.4. Quoted Strings
+//
+// Recipients that process the value of a quoted-string MUST handle a
+// quoted-pair as if it were replaced by the octet following the backslash.
+//
+// A sender SHOULD NOT generate a quoted-pair in a quoted-string except where
+// necessary to quote DQUOTE and backslash octets occurring within that
+// string.
+//
+
+// unquoteString returns the value of quotedString.
+func unquoteString(quotedString []byte) (value []byte) {
+	value = []byte{}
+	for i := 1; i < len(quotedString)-1; i++ {
+		if quotedString[i] == '\\' {
+			i++
+		}
+		value = append(value, quotedString[i])
+	}
+	return
+}
+
+// quoteIfNeeded returns value as is if it is a token, or otherwise as a
+// quoted-string.
+func quoteIfNeeded(value []byte) []byte {
+	if matchesAll(value, NewTokenFinder()) {
+		return value
+	}
+	quotedString := []byte{'"'}
+	for _, b := range value {
+		if b == '"' || b == '\\' {
+			quotedString = append(quotedString, '\\')
+		}
+		quotedString = append(quotedString, b)
+	}
+	return append(quotedString, '"')
+}
@@ -0,0 +1,172 @@
+package http11p
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestFieldSection() FieldSection {
+	return FieldSection{
+		{FieldName: []byte("Content-Type"), FieldValue: []byte("text/html")},
+		{FieldName: []byte("Set-Cookie"), FieldValue: []byte("a=1")},
+		{FieldName: []byte("Cache-Control"), FieldValue: []byte("no-cache")},
+		{FieldName: []byte("set-cookie"), FieldValue: []byte("b=2")},
+	}
+}
+
+func fieldSectionString(fieldSection FieldSection) (str string) {
+	for _, fieldLine := range fieldSection {
+		str += string(fieldLine.FieldName) + ": " + string(fieldLine.FieldValue) + "\n"
+	}
+	return
+}
+
+func TestFieldSectionGet(t *testing.T) {
+	fieldSection := newTestFieldSection()
+	tests := []struct {
+		name     string
+		expected []byte
+	}{
+		{name: "Content-Type", expected: []byte("text/html")},
+		{name: "content-type", expected: []byte("text/html")},
+		{name: "SET-COOKIE", expected: []byte("a=1")},
+		{name: "Content-Length", expected: nil},
+		{name: "Content-Typ", expected: nil},
+	}
+	for _, test := range tests {
+		actual := fieldSection.Get(test.name)
+		if !byteEquals(test.expected, actual) || (test.expected == nil) != (actual == nil) {
+			t.Errorf("%v: expected: %s, actual: %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestFieldSectionValues(t *testing.T) {
+	fieldSection := newTestFieldSection()
+	values := fieldSection.Values("Set-Cookie")
+	if len(values) != 2 {
+		t.Errorf("expected: 2 values, actual: %s", values)
+		return
+	}
+	if string(values[0]) != "a=1" || string(values[1]) != "b=2" {
+		t.Errorf("expected: [a=1 b=2], actual: %s", values)
+	}
+	if len(fieldSection.Values("Content-Length")) != 0 {
+		t.Errorf("expected: no values, actual: %s", fieldSection.Values("Content-Length"))
+	}
+}
+
+func TestFieldSectionHas(t *testing.T) {
+	fieldSection := newTestFieldSection()
+	equals("cache-control", t, true, fieldSection.Has("cache-control"))
+	equals("Content-Length", t, false, fieldSection.Has("Content-Length"))
+	// KELVIN SIGN folds to "k" in Unicode, but field-name is a token.
+	equals("KELVIN SIGN", t, false, FieldSection{
+		{FieldName: []byte("k"), FieldValue: []byte("1")},
+	}.Has("\u212a"))
+}
+
+func TestFieldSectionAdd(t *testing.T) {
+	fieldSection := newTestFieldSection()
+	fieldSection.Add("Set-Cookie", []byte("c=3"))
+	expected := "Content-Type: text/html\n" +
+		"Set-Cookie: a=1\n" +
+		"Cache-Control: no-cache\n" +
+		"set-cookie: b=2\n" +
+		"Set-Cookie: c=3\n"
+	equals("Add", t, expected, fieldSectionString(fieldSection))
+}
+
+func TestFieldSectionSet(t *testing.T) {
+	fieldSection := newTestFieldSection()
+	fieldSection.Set("SET-COOKIE", []byte("c=3"))
+	expected := "Content-Type: text/html\n" +
+		"Set-Cookie: c=3\n" +
+		"Cache-Control: no-cache\n"
+	equals("Set existing", t, expected, fieldSectionString(fieldSection))
+
+	fieldSection.Set("Content-Length", []byte("7"))
+	expected += "Content-Length: 7\n"
+	equals("Set new", t, expected, fieldSectionString(fieldSection))
+}
+
+func TestFieldSectionDel(t *testing.T) {
+	fieldSection := newTestFieldSection()
+	fieldSection.Del("set-cookie")
+	expected := "Content-Type: text/html\n" +
+		"Cache-Control: no-cache\n"
+	equals("Del", t, expected, fieldSectionString(fieldSection))
+
+	var empty FieldSection
+	empty.Del("Set-Cookie")
+	equals("Del from empty", t, 0, len(empty))
+}
+
+func TestFieldSectionRange(t *testing.T) {
+	fieldSection := newTestFieldSection()
+	names := []string{}
+	fieldSection.Range(func(fieldName []byte, fieldValue []byte) bool {
+		names = append(names, string(fieldName))
+		return len(names) < 3
+	})
+	if len(names) != 3 || names[0] != "Content-Type" || names[1] != "Set-Cookie" || names[2] != "Cache-Control" {
+		t.Errorf("expected: [Content-Type Set-Cookie Cache-Control], actual: %v", names)
+	}
+}
+
+func TestFieldSectionListMembers(t *testing.T) {
+	tests := []struct {
+		testName     string
+		fieldSection FieldSection
+		name         string
+		expected     []string
+	}{
+		{
+			testName: "single field line",
+			fieldSection: FieldSection{
+				{FieldName: []byte("Accept-Encoding"), FieldValue: []byte("gzip, deflate")},
+			},
+			name:     "Accept-Encoding",
+			expected: []string{"gzip", "deflate"},
+		},
+		{
+			testName: "multiple field lines",
+			fieldSection: FieldSection{
+				{FieldName: []byte("Accept-Encoding"), FieldValue: []byte("gzip")},
+				{FieldName: []byte("Content-Type"), FieldValue: []byte("text/html")},
+				{FieldName: []byte("accept-encoding"), FieldValue: []byte("br")},
+			},
+			name:     "Accept-Encoding",
+			expected: []string{"gzip", "br"},
+		},
+		{
+			testName: "empty elements",
+			fieldSection: FieldSection{
+				{FieldName: []byte("Accept-Encoding"), FieldValue: []byte(", gzip,, \tbr ,")},
+			},
+			name:     "Accept-Encoding",
+			expected: []string{"gzip", "br"},
+		},
+		{
+			testName: "comma in quoted-string",
+			fieldSection: FieldSection{
+				{FieldName: []byte("Cache-Control"), FieldValue: []byte("no-cache=\"a, b\", private")},
+			},
+			name:     "Cache-Control",
+			expected: []string{"no-cache=\"a, b\"", "private"},
+		},
+		{
+			testName:     "no field line",
+			fieldSection: FieldSection{},
+			name:         "Accept-Encoding",
+			expected:     []string{},
+		},
+	}
+	for _, test := range tests {
+		actual := []string{}
+		for _, member := range test.fieldSection.ListMembers(test.name) {
+			actual = append(actual, string(member))
+		}
+		equals(test.testName, t, fmt.Sprint(test.expected), fmt.Sprint(actual))
+	}
+}
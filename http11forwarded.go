@@ -0,0 +1,170 @@
+package http11p
+
+import (
+	"bytes"
+
+	abnfp "github.com/um7a/abnf-parser"
+	urip "github.com/um7a/uri-parser"
+)
+
+// RFC7239 - 4. Forwarded HTTP Header Field
+//
+// Each parameter MUST NOT occur more than once per forwarded-element. Note
+// that as ":" and "[]" are not valid characters in "token", IPv6 addresses
+// are written as "quoted-string".
+//
+// A proxy server that wants to add a new "Forwarded" header field value can
+// either append it to the last existing "Forwarded" header field after a
+// comma separator or add a new field at the end of the header block.
+//
+// RFC7239 - 5. Parameters
+//
+// The "by" and "for" parameters use the node syntax of Section 6. The syntax
+// for a "host" value, after potential quoted-string unescaping, MUST conform
+// to the Host ABNF. The syntax of a "proto" value, after potential
+// quoted-string unescaping, MUST conform to the URI scheme name.
+//
+
+// Forwarded is a forwarded-element of the Forwarded field. The values are
+// unquoted, e.g. For is "[2001:db8:cafe::17]:4711", and empty if the
+// parameter is absent.
+type Forwarded struct {
+	For   []byte
+	By    []byte
+	Host  []byte
+	Proto []byte
+
+	// Extensions is the other forwarded-pairs in order.
+	Extensions []ForwardedPair
+}
+
+// ForwardedPair is a forwarded-pair with its value unquoted.
+type ForwardedPair struct {
+	Name  []byte
+	Value []byte
+}
+
+// Forwarded returns the elements of the Forwarded field in order. If the
+// Forwarded field is invalid, the error is a *ParseError wrapping
+// ErrInvalidForwarded.
+func (fieldSection FieldSection) Forwarded() (forwardeds []Forwarded, err error) {
+	forwardeds = []Forwarded{}
+	elements, ok := fieldSection.listElements("Forwarded", NewForwardedElementFinder())
+	if !ok {
+		return forwardeds, newInvalidForwardedError("invalid Forwarded")
+	}
+	for _, element := range elements {
+		forwarded, err := marshalForwarded(element)
+		if err != nil {
+			return forwardeds, err
+		}
+		forwardeds = append(forwardeds, forwarded)
+	}
+	return forwardeds, nil
+}
+
+func newInvalidForwardedError(message string) error {
+	return newParseError(ErrInvalidForwarded, "Forwarded", message, nil)
+}
+
+// marshalForwarded splits forwarded-element, which is checked by
+// NewForwardedElementFinder, and checks its parameters.
+func marshalForwarded(data []byte) (forwarded Forwarded, err error) {
+	forwarded.Extensions = []ForwardedPair{}
+	seen := map[string]bool{}
+	remaining := data
+	for len(remaining) > 0 {
+		if remaining[0] == ';' {
+			remaining = remaining[1:]
+			continue
+		}
+		var pair ForwardedPair
+		pair.Name, remaining = abnfp.Parse(remaining, NewTokenFinder())
+		// Skip "=".
+		remaining = remaining[1:]
+		var value []byte
+		value, remaining = abnfp.Parse(remaining, NewQuotedStringFinder())
+		if len(value) > 0 {
+			pair.Value = unquoteString(value)
+		} else {
+			pair.Value, remaining = abnfp.Parse(remaining, NewTokenFinder())
+		}
+
+		name := string(bytes.ToLower(pair.Name))
+		if seen[name] {
+			return forwarded, newInvalidForwardedError("parameter " + name + " occurs more than once")
+		}
+		seen[name] = true
+
+		switch name {
+		case "for":
+			forwarded.For = pair.Value
+		case "by":
+			forwarded.By = pair.Value
+		case "host":
+			forwarded.Host = pair.Value
+		case "proto":
+			forwarded.Proto = pair.Value
+		default:
+			forwarded.Extensions = append(forwarded.Extensions, pair)
+		}
+	}
+	return forwarded, forwarded.validate()
+}
+
+// validate checks the syntax of the parameters of RFC7239 Section 5.
+func (forwarded Forwarded) validate() error {
+	if len(forwarded.For) > 0 && !matchesAll(forwarded.For, NewNodeFinder()) {
+		return newInvalidForwardedError("invalid for")
+	}
+	if len(forwarded.By) > 0 && !matchesAll(forwarded.By, NewNodeFinder()) {
+		return newInvalidForwardedError("invalid by")
+	}
+	if len(forwarded.Host) > 0 && !matchesAll(forwarded.Host, NewHostFinder()) {
+		return newInvalidForwardedError("invalid host")
+	}
+	if len(forwarded.Proto) > 0 && !matchesAll(forwarded.Proto, urip.NewSchemeFinder()) {
+		return newInvalidForwardedError("invalid proto")
+	}
+	return nil
+}
+
+// Unmarshal returns forwarded as forwarded-element. The values are quoted if
+// they are not tokens.
+func (forwarded Forwarded) Unmarshal() (data []byte) {
+	pairs := []ForwardedPair{
+		{Name: []byte("for"), Value: forwarded.For},
+		{Name: []byte("by"), Value: forwarded.By},
+		{Name: []byte("host"), Value: forwarded.Host},
+		{Name: []byte("proto"), Value: forwarded.Proto},
+	}
+	pairs = append(pairs, forwarded.Extensions...)
+	for _, pair := range pairs {
+		if len(pair.Value) == 0 {
+			continue
+		}
+		if len(data) > 0 {
+			data = append(data, ';')
+		}
+		data = append(data, pair.Name...)
+		data = append(data, '=')
+		data = append(data, quoteIfNeeded(pair.Value)...)
+	}
+	return
+}
+
+// AddForwarded appends forwarded to the Forwarded field. If forwarded is not
+// valid, the error is a *ParseError wrapping ErrInvalidForwarded and
+// fieldSection is not changed.
+func (fieldSection *FieldSection) AddForwarded(forwarded Forwarded) error {
+	data := forwarded.Unmarshal()
+	if len(data) == 0 || !matchesAll(data, NewForwardedElementFinder()) {
+		return newInvalidForwardedError("invalid Forwarded")
+	}
+	_, err := marshalForwarded(data)
+	if err != nil {
+		return err
+	}
+	fieldSection.addListMember("Forwarded", data)
+	return nil
+}
@@ -0,0 +1,141 @@
+package http11p
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFieldSectionForwarded(t *testing.T) {
+	tests := []struct {
+		testName    string
+		fieldValues []string
+		expectedErr error
+		expected    []Forwarded
+	}{
+		{
+			testName:    "no Forwarded",
+			fieldValues: []string{},
+			expected:    []Forwarded{},
+		},
+		{
+			testName:    "parameters",
+			fieldValues: []string{"for=192.0.2.60;proto=http;by=203.0.113.43;host=\"example.com:8080\";secret=\"a b\""},
+			expected: []Forwarded{
+				{
+					For:        []byte("192.0.2.60"),
+					By:         []byte("203.0.113.43"),
+					Host:       []byte("example.com:8080"),
+					Proto:      []byte("http"),
+					Extensions: []ForwardedPair{{Name: []byte("secret"), Value: []byte("a b")}},
+				},
+			},
+		},
+		{
+			testName:    "elements in multiple lines",
+			fieldValues: []string{"For=\"[2001:db8:cafe::17]:4711\", for=unknown", "for=_hidden"},
+			expected: []Forwarded{
+				{For: []byte("[2001:db8:cafe::17]:4711"), Extensions: []ForwardedPair{}},
+				{For: []byte("unknown"), Extensions: []ForwardedPair{}},
+				{For: []byte("_hidden"), Extensions: []ForwardedPair{}},
+			},
+		},
+		{
+			testName:    "unknown is case-insensitive",
+			fieldValues: []string{"for=UNKNOWN;by=Unknown"},
+			expected: []Forwarded{
+				{For: []byte("UNKNOWN"), By: []byte("Unknown"), Extensions: []ForwardedPair{}},
+			},
+		},
+		{
+			testName:    "duplicate parameter",
+			fieldValues: []string{"for=a;For=b"},
+			expectedErr: ErrInvalidForwarded,
+		},
+		{
+			testName:    "IPv6 address without brackets",
+			fieldValues: []string{"for=\"2001:db8:cafe::17\""},
+			expectedErr: ErrInvalidForwarded,
+		},
+		{
+			testName:    "invalid proto",
+			fieldValues: []string{"proto=\"1http\""},
+			expectedErr: ErrInvalidForwarded,
+		},
+		{
+			testName:    "no value",
+			fieldValues: []string{"for="},
+			expectedErr: ErrInvalidForwarded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			fieldSection := FieldSection{}
+			for _, fieldValue := range test.fieldValues {
+				fieldSection.Add("Forwarded", []byte(fieldValue))
+			}
+			forwardeds, err := fieldSection.Forwarded()
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("%v: expected: %v, actual: %v", test.testName, test.expectedErr, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+			equals(test.testName+" len(forwardeds)", t, len(test.expected), len(forwardeds))
+			for i := 0; i < len(test.expected) && i < len(forwardeds); i++ {
+				expected := test.expected[i]
+				actual := forwardeds[i]
+				equals(test.testName+" For", t, string(expected.For), string(actual.For))
+				equals(test.testName+" By", t, string(expected.By), string(actual.By))
+				equals(test.testName+" Host", t, string(expected.Host), string(actual.Host))
+				equals(test.testName+" Proto", t, string(expected.Proto), string(actual.Proto))
+				equals(test.testName+" len(Extensions)", t, len(expected.Extensions), len(actual.Extensions))
+				for j := 0; j < len(expected.Extensions) && j < len(actual.Extensions); j++ {
+					equals(test.testName+" Extensions.Name", t, string(expected.Extensions[j].Name), string(actual.Extensions[j].Name))
+					equals(test.testName+" Extensions.Value", t, string(expected.Extensions[j].Value), string(actual.Extensions[j].Value))
+				}
+			}
+		})
+	}
+}
+
+func TestForwardedUnmarshal(t *testing.T) {
+	forwarded := Forwarded{
+		For:        []byte("[2001:db8:cafe::17]:4711"),
+		Proto:      []byte("https"),
+		Extensions: []ForwardedPair{{Name: []byte("secret"), Value: []byte("a\"b")}},
+	}
+	expected := "for=\"[2001:db8:cafe::17]:4711\";proto=https;secret=\"a\\\"b\""
+	equals("Unmarshal", t, expected, string(forwarded.Unmarshal()))
+}
+
+func TestFieldSectionAddForwarded(t *testing.T) {
+	fieldSection := FieldSection{
+		{FieldName: []byte("Forwarded"), FieldValue: []byte("for=192.0.2.43")},
+	}
+	err := fieldSection.AddForwarded(Forwarded{For: []byte("[::1]"), By: []byte("_proxy")})
+	equals("err", t, true, err == nil)
+	equals("len(fieldSection)", t, 1, len(fieldSection))
+	equals("Forwarded", t, "for=192.0.2.43, for=\"[::1]\";by=_proxy", string(fieldSection.Get("Forwarded")))
+
+	forwardeds, err := fieldSection.Forwarded()
+	equals("err", t, true, err == nil)
+	equals("len(forwardeds)", t, 2, len(forwardeds))
+
+	tests := []struct {
+		testName  string
+		forwarded Forwarded
+	}{
+		{testName: "empty", forwarded: Forwarded{}},
+		{testName: "invalid for", forwarded: Forwarded{For: []byte("a b")}},
+		{testName: "invalid host", forwarded: Forwarded{Host: []byte("a b")}},
+	}
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			err := fieldSection.AddForwarded(test.forwarded)
+			equals(test.testName+" ErrInvalidForwarded", t, true, errors.Is(err, ErrInvalidForwarded))
+			equals(test.testName+" Forwarded", t, "for=192.0.2.43, for=\"[::1]\";by=_proxy", string(fieldSection.Get("Forwarded")))
+		})
+	}
+}
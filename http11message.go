@@ -1,8 +1,6 @@
 package http11p
 
 import (
-	"errors"
-
 	abnfp "github.com/um7a/abnf-parser"
 )
 
@@ -16,30 +14,68 @@ type FieldLine struct {
 	FieldValue []byte
 }
 
-func marshalFieldLines(data []byte) (fieldLines []FieldLine, remaining []byte, err error) {
+// marshalFieldLines parses *( field-line CRLF ) within the limits of
+// options. It stops only at the empty line which ends the field section or
+// the part of it received so far, so a missing CRLF after it means that the
+// data ends before the end of the message.
+func marshalFieldLines(data []byte, options ParserOptions) (fieldLines []FieldLine, remaining []byte, err error) {
 	fieldLines = []FieldLine{}
 	remaining = data
+	limiter := fieldSectionLimiter{options: options}
 	var fieldName []byte
 	var fieldValue []byte
 	var colon []byte
 	var crlf []byte
 
 	for {
+		// Check the limits before parsing, so that a line which is too long
+		// is reported as such even if it is not a valid field-line.
+		line := remaining
+		n := lineLength(remaining)
+		crlf, _ = abnfp.Parse(remaining, options.newEolFinder())
+		if len(crlf) == 0 {
+			err = limiter.check(n, remaining)
+			if err != nil {
+				return fieldLines, data, err
+			}
+		}
+
 		found, _ := NewFieldLineFinder().Find(remaining)
 		if !found {
+			whitespace, found := findWhitespaceBeforeColon(remaining)
+			if found {
+				return fieldLines, data, newParseError(
+					ErrWhitespaceBeforeColon,
+					"field-name",
+					"whitespace between field-name and colon",
+					whitespace,
+				)
+			}
+			// A line which is neither a field-line nor the empty line is
+			// reported as the streaming parsers do, which see one line at a
+			// time.
+			if !isPartOfEmptyLine(remaining[:n], options) {
+				err = newParseError(ErrInvalidFieldLine, "field-line", "invalid field-line", remaining)
+				return fieldLines, data, orIncomplete(err, "field-line", line, NewFieldLineFinder(), isFieldLinePrefix)
+			}
 			break
 		}
+		err = limiter.add(n, remaining)
+		if err != nil {
+			return fieldLines, data, err
+		}
 
 		// field-name
 		fieldName, remaining = abnfp.Parse(remaining, NewFieldNameFinder())
 		if len(fieldName) == 0 {
-			return fieldLines, data, errors.New("field-name not found")
+			return fieldLines, data, newParseError(ErrInvalidFieldLine, "field-name", "field-name not found", remaining)
 		}
 
 		// ":"
+		rest := remaining
 		colon, remaining = abnfp.Parse(remaining, abnfp.NewByteFinder(':'))
 		if len(colon) == 0 {
-			return fieldLines, data, errors.New("\":\" after field-name not found")
+			return fieldLines, data, newParseError(ErrInvalidFieldLine, "\":\"", "\":\" after field-name not found", rest)
 		}
 
 		// OWS
@@ -47,8 +83,22 @@ func marshalFieldLines(data []byte) (fieldLines []FieldLine, remaining []byte, e
 
 		// field-value
 		fieldValue, remaining = abnfp.Parse(remaining, NewFieldValueFinder())
-		if len(fieldValue) == 0 {
-			return fieldLines, data, errors.New("field-value not found")
+
+		// *( obs-fold field-value )
+		for {
+			obsFold, _ := abnfp.Parse(remaining, options.newObsFoldFinder())
+			if len(obsFold) == 0 {
+				break
+			}
+			continuation := remaining[lineLength(remaining):]
+			err = limiter.extend(lineLength(continuation), continuation)
+			if err != nil {
+				return fieldLines, data, err
+			}
+			fieldValue, remaining, err = unfoldFieldValue(fieldValue, continuation, options)
+			if err != nil {
+				return fieldLines, data, err
+			}
 		}
 
 		// OWS
@@ -60,11 +110,118 @@ func marshalFieldLines(data []byte) (fieldLines []FieldLine, remaining []byte, e
 		)
 
 		// CRLF
-		crlf, remaining = abnfp.Parse(remaining, abnfp.NewCrLfFinder())
+		rest = remaining
+		crlf, remaining = abnfp.Parse(remaining, options.newEolFinder())
 		if len(crlf) == 0 {
-			return fieldLines, data, errors.New("CRLF after field-line not found")
+			err = newParseError(ErrInvalidFieldLine, "CRLF", "CRLF after field-line not found", rest)
+			return fieldLines, data, orIncomplete(err, "field-line", line, NewFieldLineFinder(), isFieldLinePrefix)
+		}
+	}
+
+	return
+}
+
+// isFieldLinePrefix reports whether data is the part of field-line
+// received so far.
+func isFieldLinePrefix(data []byte) bool {
+	fieldName, remaining := abnfp.Parse(data, NewFieldNameFinder())
+	if len(remaining) == 0 {
+		return true
+	}
+	if len(fieldName) == 0 || remaining[0] != ':' {
+		return false
+	}
+	for _, b := range remaining[1:] {
+		if !isTextByte(b) {
+			return false
 		}
 	}
+	return true
+}
+
+// unfoldFieldValue appends the field-value of continuation, a line which
+// continues a field line with obs-fold, to fieldValue, replacing the
+// obs-fold with SP. It returns an error unless options allow to unfold.
+func unfoldFieldValue(fieldValue []byte, continuation []byte, options ParserOptions) (
+	unfolded []byte,
+	remaining []byte,
+	err error,
+) {
+	if options.ObsFold != ObsFoldUnfold {
+		return nil, continuation, newParseError(ErrObsFold, "obs-fold", "obs-fold in field-value", continuation)
+	}
+	_, remaining = abnfp.Parse(continuation, NewRwsFinder())
+	value, remaining := abnfp.Parse(remaining, NewFieldValueFinder())
 
+	unfolded = append([]byte{}, fieldValue...)
+	if len(unfolded) > 0 && len(value) > 0 {
+		unfolded = append(unfolded, ' ')
+	}
+	unfolded = append(unfolded, value...)
+	return unfolded, remaining, nil
+}
+
+// isContinuationLine reports whether line continues the previous field
+// line with obs-fold.
+func isContinuationLine(line []byte) bool {
+	return len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+}
+
+// unfoldFieldLine appends the field-value of line, which continues
+// fieldLine with obs-fold, to fieldLine.
+func unfoldFieldLine(fieldLine *FieldLine, line []byte, options ParserOptions) error {
+	fieldValue, remaining, err := unfoldFieldValue(fieldLine.FieldValue, line, options)
+	if err != nil {
+		return err
+	}
+	_, remaining = abnfp.Parse(remaining, NewOwsFinder())
+	rest := remaining
+	crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
+	if len(crlf) == 0 || len(remaining) != 0 {
+		return newParseError(ErrInvalidFieldLine, "CRLF", "CRLF after field-line not found", rest)
+	}
+	fieldLine.FieldValue = fieldValue
+	return nil
+}
+
+// RFC9112 - 5.1. Field Line Parsing
+//
+// No whitespace is allowed between the field name and colon. In the past,
+// differences in the handling of such whitespace have led to security
+// vulnerabilities in request routing and response handling. A server MUST
+// reject, with a response status code of 400 (Bad Request), any received
+// request message that contains whitespace between a header field name and
+// colon.
+//
+
+// findWhitespaceBeforeColon reports whether data starts with
+// field-name 1*( SP / HTAB ) ":", and returns data from the whitespace.
+func findWhitespaceBeforeColon(data []byte) (whitespace []byte, found bool) {
+	fieldName, whitespace := abnfp.Parse(data, NewFieldNameFinder())
+	if len(fieldName) == 0 {
+		return nil, false
+	}
+	ws, remaining := abnfp.Parse(whitespace, abnfp.NewVariableRepetitionMinFinder(
+		1,
+		abnfp.NewAlternativesFinder([]abnfp.Finder{abnfp.NewSpFinder(), abnfp.NewHTabFinder()}),
+	))
+	if len(ws) == 0 || len(remaining) == 0 || remaining[0] != ':' {
+		return nil, false
+	}
+	return whitespace, true
+}
+
+func unmarshalFieldLines(fieldLines []FieldLine) (data []byte) {
+	sp := []byte(" ")
+	crlf := []byte("\r\n")
+	colon := []byte(":")
+
+	for _, fieldLine := range fieldLines {
+		data = append(data, fieldLine.FieldName...)
+		data = append(data, colon...)
+		data = append(data, sp...)
+		data = append(data, fieldLine.FieldValue...)
+		data = append(data, crlf...)
+	}
 	return
 }
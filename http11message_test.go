@@ -26,7 +26,7 @@ func TestMarshalFieldLines(t *testing.T) {
 	}
 
 	execTest := func(test TestCaseMarshalFieldLines) {
-		fieldLines, remaining, err := marshalFieldLines(test.data)
+		fieldLines, remaining, err := marshalFieldLines(test.data, DefaultParserOptions())
 		// Check err
 		if err != nil {
 			if test.err {
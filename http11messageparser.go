@@ -0,0 +1,546 @@
+package http11p
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// ErrNeedMoreData is returned by RequestParser and ResponseParser when the
+// data fed so far is a valid but incomplete message.
+var ErrNeedMoreData = errors.New("need more data")
+
+type parserState int
+
+const (
+	stateStartLine parserState = iota
+	stateFieldLines
+	stateContentLengthBody
+	stateChunkSize
+	stateChunkData
+	stateTrailerSection
+	stateUntilCloseBody
+	stateDone
+)
+
+// messageParser holds the state shared by RequestParser and ResponseParser.
+// Each element of the message is parsed once; when the buffered data ends
+// in the middle of an element, parsing resumes from that element on the
+// next call of feed.
+type messageParser struct {
+	buf     []byte
+	scanned int
+	state   parserState
+
+	// pos is the position of buf in the message, which is used to locate
+	// a ParseError.
+	pos position
+
+	options ParserOptions
+	limiter fieldSectionLimiter
+
+	// err is the error which stopped parsing. feed returns it until reset.
+	err error
+
+	checkStartLine   func(n int, data []byte) error
+	marshalStartLine func(line []byte) error
+	getBodyFraming   func() (bodyFraming, int64, error)
+
+	framing         bodyFraming
+	fieldLines      []FieldLine
+	fieldLineStarts []fieldLineStart
+	body            []byte
+	chunkExtensions []ChunkExtension
+	trailerSection  []FieldLine
+	bodyRemaining   int64
+	bodyStart       position
+}
+
+func (p *messageParser) reset() {
+	p.scanned = 0
+	p.err = nil
+	p.state = stateStartLine
+	p.pos = startPosition
+	p.limiter = fieldSectionLimiter{options: p.options}
+	p.framing = framingNone
+	p.fieldLines = []FieldLine{}
+	p.fieldLineStarts = nil
+	p.body = []byte{}
+	p.chunkExtensions = nil
+	p.trailerSection = nil
+	p.bodyRemaining = 0
+	p.bodyStart = startPosition
+}
+
+// nextLine returns the next line including its line terminator, or nil if
+// the buffer does not contain a whole line yet. check is called with the
+// length of the line, or of the part of it buffered so far, so that a line
+// which is too long is rejected before it is complete.
+func (p *messageParser) nextLine(check func(n int, data []byte) error) (line []byte, err error) {
+	i := bytes.IndexByte(p.buf[p.scanned:], '\n')
+	if i < 0 {
+		p.scanned = len(p.buf)
+		err = check(len(p.buf), p.buf)
+		if err != nil {
+			return nil, locateErrorAt(err, p.pos, p.buf)
+		}
+		return nil, nil
+	}
+	line = p.buf[:p.scanned+i+1]
+	err = check(len(line), line)
+	if err != nil {
+		return nil, locateErrorAt(err, p.pos, line)
+	}
+	p.consume(len(line))
+	return line, nil
+}
+
+// checkFieldLine checks a line in the header section or the trailer
+// section, except the empty line at its end.
+func (p *messageParser) checkFieldLine(n int, data []byte) error {
+	if isPartOfEmptyLine(data, p.options) {
+		return nil
+	}
+	return p.limiter.check(n, data)
+}
+
+// isPartOfEmptyLine reports whether data is the empty line at the end of a
+// field section, or the part of it received so far, e.g. the CR of a CRLF
+// whose LF has not arrived yet.
+func isPartOfEmptyLine(data []byte, options ParserOptions) bool {
+	crlf, _ := abnfp.Parse(data, options.newEolFinder())
+	return len(crlf) == len(data) || bytes.Equal(data, []byte("\r"))
+}
+
+// consume removes the first n bytes of buf.
+func (p *messageParser) consume(n int) {
+	p.pos = p.pos.advance(p.buf[:n])
+	p.buf = p.buf[n:]
+	p.scanned = 0
+}
+
+// feed appends data to buf and parses it. Once it fails, it returns the
+// same error without parsing until reset.
+func (p *messageParser) feed(data []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.buf = append(p.buf, data...)
+	err := p.parse()
+	if err != nil && err != ErrNeedMoreData {
+		p.err = err
+	}
+	return err
+}
+
+func (p *messageParser) parse() (err error) {
+	for {
+		switch p.state {
+		case stateStartLine:
+			pos := p.pos
+			line, err := p.nextLine(p.checkStartLine)
+			if err != nil {
+				return err
+			}
+			if line == nil {
+				return ErrNeedMoreData
+			}
+			if len(p.options.skipEmptyLines(line)) == 0 {
+				// An empty line before the start-line in the lenient mode.
+				continue
+			}
+			err = p.marshalStartLine(line)
+			if err != nil {
+				return locateErrorAt(err, pos, line)
+			}
+			p.state = stateFieldLines
+
+		case stateFieldLines:
+			pos := p.pos
+			line, err := p.nextLine(p.checkFieldLine)
+			if err != nil {
+				return err
+			}
+			if line == nil {
+				return ErrNeedMoreData
+			}
+			crlf, _ := abnfp.Parse(line, p.options.newEolFinder())
+			if len(crlf) == len(line) {
+				err = p.startMessageBody()
+				if err != nil {
+					return locateFieldLineError(err, p.fieldLineStarts)
+				}
+				continue
+			}
+			err = p.addFieldLine(&p.fieldLines, line)
+			if err != nil {
+				return locateErrorAt(err, pos, line)
+			}
+			if !isContinuationLine(line) {
+				p.fieldLineStarts = append(p.fieldLineStarts, fieldLineStart{pos: pos, rest: line})
+			}
+
+		case stateContentLengthBody:
+			if int64(len(p.buf)) < p.bodyRemaining {
+				p.body = append(p.body, p.buf...)
+				p.bodyRemaining -= int64(len(p.buf))
+				p.consume(len(p.buf))
+				return ErrNeedMoreData
+			}
+			p.body = append(p.body, p.buf[:p.bodyRemaining]...)
+			p.consume(int(p.bodyRemaining))
+			p.bodyRemaining = 0
+			p.state = stateDone
+
+		case stateChunkSize:
+			pos := p.pos
+			line, err := p.nextLine(p.options.checkChunkSizeLine)
+			if err != nil {
+				return err
+			}
+			if line == nil {
+				return ErrNeedMoreData
+			}
+			size, chunkExtensions, err := marshalChunkSizeLine(line, p.options)
+			if err != nil {
+				return locateErrorAt(err, pos, line)
+			}
+			p.chunkExtensions = append(p.chunkExtensions, chunkExtensions...)
+			if size == 0 {
+				// last-chunk
+				p.trailerSection = []FieldLine{}
+				p.limiter = fieldSectionLimiter{options: p.options}
+				p.state = stateTrailerSection
+				continue
+			}
+			err = p.options.checkBodySize(addBodySize(int64(len(p.body)), size), []byte{})
+			if err != nil {
+				return locateErrorAt(err, p.bodyStart, []byte{})
+			}
+			p.bodyRemaining = size
+			p.state = stateChunkData
+
+		case stateChunkData:
+			// chunk-data CRLF
+			// The length after chunk-data is compared instead of adding the
+			// length of CRLF to bodyRemaining, which can overflow.
+			if int64(len(p.buf))-p.bodyRemaining < 1 {
+				return ErrNeedMoreData
+			}
+			rest := p.buf[p.bodyRemaining:]
+			crlf, _ := abnfp.Parse(rest, p.options.newEolFinder())
+			if len(crlf) == 0 {
+				// Wait for the LF only after a CR.
+				if isPartOfEmptyLine(rest, p.options) {
+					return ErrNeedMoreData
+				}
+				err = newParseError(ErrInvalidChunkedBody, "CRLF", "CRLF after chunk-data not found", rest)
+				return locateErrorAt(err, p.pos, p.buf)
+			}
+			p.body = append(p.body, p.buf[:p.bodyRemaining]...)
+			p.consume(int(p.bodyRemaining) + len(crlf))
+			p.bodyRemaining = 0
+			p.state = stateChunkSize
+
+		case stateTrailerSection:
+			pos := p.pos
+			line, err := p.nextLine(p.checkFieldLine)
+			if err != nil {
+				return err
+			}
+			if line == nil {
+				return ErrNeedMoreData
+			}
+			crlf, _ := abnfp.Parse(line, p.options.newEolFinder())
+			if len(crlf) == len(line) {
+				p.state = stateDone
+				continue
+			}
+			err = p.addFieldLine(&p.trailerSection, line)
+			if err != nil {
+				return locateErrorAt(err, pos, line)
+			}
+
+		case stateUntilCloseBody:
+			err = p.options.checkBodySize(int64(len(p.body)+len(p.buf)), []byte{})
+			if err != nil {
+				return locateErrorAt(err, p.bodyStart, []byte{})
+			}
+			p.body = append(p.body, p.buf...)
+			p.consume(len(p.buf))
+			return ErrNeedMoreData
+
+		case stateDone:
+			return nil
+		}
+	}
+}
+
+// addFieldLine parses line and adds it to fieldLines, or to the last field
+// line if line continues it with obs-fold.
+func (p *messageParser) addFieldLine(fieldLines *[]FieldLine, line []byte) error {
+	if len(*fieldLines) > 0 && isContinuationLine(line) {
+		err := p.limiter.extend(len(line), line)
+		if err != nil {
+			return err
+		}
+		return unfoldFieldLine(&(*fieldLines)[len(*fieldLines)-1], line, p.options)
+	}
+
+	err := p.limiter.add(len(line), line)
+	if err != nil {
+		return err
+	}
+	fieldLine, err := marshalFieldLine(line, p.options)
+	if err != nil {
+		return err
+	}
+	*fieldLines = append(*fieldLines, fieldLine)
+	return nil
+}
+
+func (p *messageParser) startMessageBody() error {
+	framing, length, err := p.getBodyFraming()
+	if err != nil {
+		return err
+	}
+	p.framing = framing
+	p.bodyStart = p.pos
+	switch framing {
+	case framingContentLength:
+		err = p.options.checkBodySize(length, p.buf)
+		if err != nil {
+			return locateErrorAt(err, p.pos, p.buf)
+		}
+		p.bodyRemaining = length
+		p.state = stateContentLengthBody
+	case framingChunked:
+		p.chunkExtensions = []ChunkExtension{}
+		p.state = stateChunkSize
+	case framingUntilClose:
+		p.state = stateUntilCloseBody
+	default:
+		// framingNone and framingTunnel
+		p.state = stateDone
+	}
+	return nil
+}
+
+func (p *messageParser) finish() error {
+	if p.err != nil {
+		return p.err
+	}
+	switch p.state {
+	case stateDone:
+		return nil
+	case stateUntilCloseBody:
+		p.state = stateDone
+		return nil
+	case stateStartLine:
+		if len(p.buf) == 0 {
+			return io.EOF
+		}
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// marshalFieldLine parses a line which consists of exactly one
+// field-line CRLF.
+func marshalFieldLine(line []byte, options ParserOptions) (fieldLine FieldLine, err error) {
+	// The limits are checked by the callers.
+	fieldLines, remaining, err := marshalFieldLines(line, ParserOptions{Lenient: options.Lenient})
+	if err != nil {
+		return FieldLine{}, err
+	}
+	if len(fieldLines) != 1 || len(remaining) != 0 {
+		return FieldLine{}, newParseError(ErrInvalidFieldLine, "field-line", "invalid field-line", line)
+	}
+	return fieldLines[0], nil
+}
+
+// marshalChunkSizeLine parses a line which consists of
+// chunk-size [ chunk-ext ] CRLF.
+func marshalChunkSizeLine(line []byte, options ParserOptions) (size int64, chunkExtensions []ChunkExtension, err error) {
+	chunkSize, remaining := abnfp.Parse(line, NewChunkSizeFinder())
+	if len(chunkSize) == 0 {
+		return 0, nil, newParseError(ErrInvalidChunkedBody, "chunk-size", "chunk-size not found", line)
+	}
+	size, err = strconv.ParseInt(string(chunkSize), 16, 64)
+	if err != nil {
+		return 0, nil, newParseError(ErrInvalidChunkedBody, "chunk-size", "invalid chunk-size", line)
+	}
+
+	chunkExt, remaining := abnfp.Parse(remaining, abnfp.NewOptionalSequenceFinder(NewChunkExtFinder()))
+	rest := remaining
+	crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
+	if len(crlf) == 0 || len(remaining) != 0 {
+		return 0, nil, newParseError(ErrInvalidChunkedBody, "CRLF", "CRLF after chunk-size not found", rest)
+	}
+	return size, marshalChunkExt(chunkExt), nil
+}
+
+// RequestParser parses a request from data fed in successive chunks, e.g.
+// as it is read from a socket.
+type RequestParser struct {
+	req    Http11Request
+	parser messageParser
+}
+
+func NewRequestParser() *RequestParser {
+	return NewRequestParserWithOptions(DefaultParserOptions())
+}
+
+// NewRequestParserWithOptions returns a RequestParser which parses with
+// options instead of DefaultParserOptions.
+func NewRequestParserWithOptions(options ParserOptions) *RequestParser {
+	p := &RequestParser{}
+	p.parser.options = options
+	p.parser.checkStartLine = options.checkRequestLine
+	p.parser.marshalStartLine = func(line []byte) error {
+		remaining, err := marshalRequestLine(line, &p.req)
+		if err != nil {
+			return err
+		}
+		rest := remaining
+		crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
+		if len(crlf) == 0 || len(remaining) != 0 {
+			return newParseError(ErrInvalidRequestLine, "CRLF", "CRLF after request-line not found", rest)
+		}
+		return nil
+	}
+	p.parser.getBodyFraming = func() (bodyFraming, int64, error) {
+		err := checkTransferEncodingVersion(p.req.HttpVersion, p.parser.fieldLines)
+		if err != nil {
+			return framingNone, 0, err
+		}
+		return getBodyFraming(p.parser.fieldLines, true)
+	}
+	p.parser.reset()
+	return p
+}
+
+// Feed appends data to the parser and continues parsing from where the
+// previous call stopped. It returns nil once a whole request has been
+// parsed, ErrNeedMoreData if the request is not complete yet, or any
+// other error if the data is not a valid request. After such an error, Feed
+// and Finish return the same error until Reset.
+func (p *RequestParser) Feed(data []byte) error {
+	return p.parser.feed(data)
+}
+
+// Finish tells the parser that no more data will be fed. It returns
+// io.EOF if no data was fed for the current request, and
+// io.ErrUnexpectedEOF if the current request is incomplete.
+func (p *RequestParser) Finish() error {
+	return p.parser.finish()
+}
+
+// Request returns the request parsed so far.
+func (p *RequestParser) Request() *Http11Request {
+	p.req.FieldLines = p.parser.fieldLines
+	p.req.MessageBody = p.parser.body
+	p.req.ChunkExtensions = p.parser.chunkExtensions
+	p.req.TrailerSection = p.parser.trailerSection
+	return &p.req
+}
+
+// Remaining returns the data fed after the end of the parsed request.
+func (p *RequestParser) Remaining() []byte {
+	return p.parser.buf
+}
+
+// Reset prepares the parser for the next request. The remaining data is
+// kept and parsed by the next call of Feed.
+func (p *RequestParser) Reset() {
+	p.req = Http11Request{}
+	p.parser.reset()
+}
+
+// ResponseParser parses a response from data fed in successive chunks,
+// e.g. as it is read from a socket.
+type ResponseParser struct {
+	resp   Http11Response
+	parser messageParser
+}
+
+func NewResponseParser() *ResponseParser {
+	return NewResponseParserForRequest(nil)
+}
+
+// NewResponseParserForRequest returns a ResponseParser which also applies
+// the message body length rules which depend on method, the method of the
+// request the responses answer. After a 2xx response to CONNECT, Remaining
+// returns the data which belongs to the tunnel.
+func NewResponseParserForRequest(method []byte) *ResponseParser {
+	return NewResponseParserWithOptions(method, DefaultParserOptions())
+}
+
+// NewResponseParserWithOptions is like NewResponseParserForRequest, but the
+// ResponseParser parses with options instead of DefaultParserOptions. method
+// can be nil if the request method is unknown.
+func NewResponseParserWithOptions(method []byte, options ParserOptions) *ResponseParser {
+	p := &ResponseParser{}
+	p.parser.options = options
+	p.parser.checkStartLine = options.checkStartLine
+	p.parser.marshalStartLine = func(line []byte) error {
+		remaining, err := marshalStatusLine(line, &p.resp)
+		if err != nil {
+			return err
+		}
+		rest := remaining
+		crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
+		if len(crlf) == 0 || len(remaining) != 0 {
+			return newParseError(ErrInvalidStatusLine, "CRLF", "CRLF after status-line not found", rest)
+		}
+		return nil
+	}
+	p.parser.getBodyFraming = func() (bodyFraming, int64, error) {
+		return getResponseBodyFraming(method, p.resp.HttpVersion, p.resp.StatusCode, p.parser.fieldLines)
+	}
+	p.parser.reset()
+	return p
+}
+
+// Feed appends data to the parser and continues parsing from where the
+// previous call stopped. It returns nil once a whole response has been
+// parsed, ErrNeedMoreData if the response is not complete yet, or any
+// other error if the data is not a valid response. After such an error, Feed
+// and Finish return the same error until Reset.
+//
+// A message-body delimited by the closing of the connection is completed
+// by Finish.
+func (p *ResponseParser) Feed(data []byte) error {
+	return p.parser.feed(data)
+}
+
+// Finish tells the parser that no more data will be fed. It returns
+// io.EOF if no data was fed for the current response, and
+// io.ErrUnexpectedEOF if the current response is incomplete.
+func (p *ResponseParser) Finish() error {
+	return p.parser.finish()
+}
+
+// Response returns the response parsed so far.
+func (p *ResponseParser) Response() *Http11Response {
+	p.resp.FieldLines = p.parser.fieldLines
+	p.resp.MessageBody = p.parser.body
+	p.resp.ChunkExtensions = p.parser.chunkExtensions
+	p.resp.TrailerSection = p.parser.trailerSection
+	p.resp.CloseDelimited = p.parser.framing == framingUntilClose
+	return &p.resp
+}
+
+// Remaining returns the data fed after the end of the parsed response.
+func (p *ResponseParser) Remaining() []byte {
+	return p.parser.buf
+}
+
+// Reset prepares the parser for the next response. The remaining data is
+// kept and parsed by the next call of Feed.
+func (p *ResponseParser) Reset() {
+	p.resp = Http11Response{}
+	p.parser.reset()
+}
@@ -0,0 +1,338 @@
+package http11p
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type TestCaseForRequestParserFeed struct {
+	testName            string
+	chunks              [][]byte
+	err                 bool
+	expectedMethod      []byte
+	expectedFieldLines  []FieldLine
+	expectedMessageBody []byte
+	expectedRemaining   []byte
+}
+
+func execTestForRequestParserFeed(tests []TestCaseForRequestParserFeed, t *testing.T) {
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			p := NewRequestParser()
+			var err error
+			for i, chunk := range testCase.chunks {
+				err = p.Feed(chunk)
+				if i < len(testCase.chunks)-1 && err != ErrNeedMoreData {
+					break
+				}
+			}
+
+			if err != nil && testCase.err == false {
+				t.Errorf("Failed to parse Http/1.1 Request: %v", err.Error())
+				return
+			}
+			if err == nil && testCase.err == true {
+				t.Errorf("Unexpectedly parse Http/1.1 Request successfully: %v", p.Request())
+				return
+			}
+			if err != nil && testCase.err == true {
+				if errors.Is(err, ErrNeedMoreData) {
+					t.Errorf("expected syntax error, actual: %v", err)
+				}
+				return
+			}
+
+			req := p.Request()
+			if !byteEquals(testCase.expectedMethod, req.Method) {
+				t.Errorf("expectedMethod: %s, actual: %s", testCase.expectedMethod, req.Method)
+			}
+			if len(testCase.expectedFieldLines) != len(req.FieldLines) {
+				t.Errorf("expectedFieldLines: %s, actual: %s", testCase.expectedFieldLines, req.FieldLines)
+				return
+			}
+			for i, expected := range testCase.expectedFieldLines {
+				if !byteEquals(expected.FieldName, req.FieldLines[i].FieldName) ||
+					!byteEquals(expected.FieldValue, req.FieldLines[i].FieldValue) {
+					t.Errorf("expectedFieldLine: %s, actual: %s", expected, req.FieldLines[i])
+				}
+			}
+			if !byteEquals(testCase.expectedMessageBody, req.MessageBody) {
+				t.Errorf("expectedMessageBody: %s, actual: %s", testCase.expectedMessageBody, req.MessageBody)
+			}
+			if !byteEquals(testCase.expectedRemaining, p.Remaining()) {
+				t.Errorf("expectedRemaining: %s, actual: %s", testCase.expectedRemaining, p.Remaining())
+			}
+		})
+	}
+}
+
+func TestRequestParserFeed(t *testing.T) {
+	tests := []TestCaseForRequestParserFeed{
+		{
+			testName: "whole request at once",
+			chunks: [][]byte{
+				[]byte("POST / HTTP/1.1\r\nContent-Length: 7\r\n\r\nabcdefg"),
+			},
+			err:            false,
+			expectedMethod: []byte("POST"),
+			expectedFieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+			},
+			expectedMessageBody: []byte("abcdefg"),
+			expectedRemaining:   []byte{},
+		},
+		{
+			testName: "request split in the middle of each element",
+			chunks: [][]byte{
+				[]byte("PO"),
+				[]byte("ST / HTTP/1.1\r"),
+				[]byte("\nContent-Len"),
+				[]byte("gth: 7\r\n"),
+				[]byte("\r"),
+				[]byte("\nabc"),
+				[]byte("defgGET"),
+			},
+			err:            false,
+			expectedMethod: []byte("POST"),
+			expectedFieldLines: []FieldLine{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("7")},
+			},
+			expectedMessageBody: []byte("abcdefg"),
+			expectedRemaining:   []byte("GET"),
+		},
+		{
+			testName: "chunked request split in the middle of each chunk",
+			chunks: [][]byte{
+				[]byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n"),
+				[]byte("3;foo=bar\r\nab"),
+				[]byte("c\r\n4\r\nde"),
+				[]byte("fg\r\n0\r\nExpi"),
+				[]byte("res: 0\r\n\r\n"),
+			},
+			err:            false,
+			expectedMethod: []byte("POST"),
+			expectedFieldLines: []FieldLine{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked")},
+			},
+			expectedMessageBody: []byte("abcdefg"),
+			expectedRemaining:   []byte{},
+		},
+		{
+			testName: "invalid request-line",
+			chunks: [][]byte{
+				[]byte("GET  / HTTP/1.1\r\n"),
+			},
+			err: true,
+		},
+		{
+			testName: "request-target is not absolute-URI",
+			chunks: [][]byte{
+				[]byte("GET ab HTTP/1.1\r\n\r\n"),
+			},
+			err: true,
+		},
+		{
+			testName: "SP in method",
+			chunks: [][]byte{
+				[]byte("P ST / HTTP/1.1\r\n\r\n"),
+			},
+			err: true,
+		},
+		{
+			testName: "invalid field-line",
+			chunks: [][]byte{
+				[]byte("GET / HTTP/1.1\r\n"),
+				[]byte("Content-Length 7\r\n"),
+			},
+			err: true,
+		},
+		{
+			testName: "invalid chunk-size",
+			chunks: [][]byte{
+				[]byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n"),
+				[]byte("xyz\r\n"),
+			},
+			err: true,
+		},
+	}
+	execTestForRequestParserFeed(tests, t)
+}
+
+func TestRequestParserFeedLargeChunkSize(t *testing.T) {
+	tests := []struct {
+		testName string
+		options  ParserOptions
+		expected error
+	}{
+		{testName: "no limit", options: ParserOptions{}, expected: ErrNeedMoreData},
+		{testName: "MaxBodySize", options: ParserOptions{MaxBodySize: 1024}, expected: ErrBodyTooLarge},
+	}
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			p := NewRequestParserWithOptions(test.options)
+			err := p.Feed([]byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n1\r\na\r\n7fffffffffffffff\r\nxx"))
+			if !errors.Is(err, test.expected) {
+				t.Errorf("%v: expected: %v, actual: %v", test.testName, test.expected, err)
+			}
+		})
+	}
+}
+
+func TestRequestParserNeedMoreData(t *testing.T) {
+	p := NewRequestParser()
+	err := p.Feed([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n"))
+	if err != ErrNeedMoreData {
+		t.Errorf("expected: %v, actual: %v", ErrNeedMoreData, err)
+	}
+	err = p.Finish()
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("expected: %v, actual: %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+// Only a CR after chunk-data waits for the LF of CRLF.
+func TestRequestParserFeedAfterChunkData(t *testing.T) {
+	tests := []struct {
+		testName string
+		data     []byte
+		expected error
+	}{
+		{testName: "CR", data: []byte("3\r\nabc\r"), expected: ErrNeedMoreData},
+		{testName: "not CR", data: []byte("3\r\nabcX"), expected: ErrInvalidChunkedBody},
+	}
+	for _, test := range tests {
+		p := NewRequestParser()
+		err := p.Feed(append([]byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n"), test.data...))
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%v: expected: %v, actual: %v", test.testName, test.expected, err)
+		}
+	}
+}
+
+func TestRequestParserReset(t *testing.T) {
+	p := NewRequestParser()
+	err := p.Feed([]byte(
+		"GET /1 HTTP/1.1\r\n\r\n" +
+			"GET /2 HTTP/1.1\r\n\r\n",
+	))
+	if err != nil {
+		t.Errorf("Failed to parse Http/1.1 Request: %v", err.Error())
+		return
+	}
+	if string(p.Request().RequestTarget) != "/1" {
+		t.Errorf("expected: /1, actual: %s", p.Request().RequestTarget)
+	}
+
+	p.Reset()
+	err = p.Feed(nil)
+	if err != nil {
+		t.Errorf("Failed to parse Http/1.1 Request: %v", err.Error())
+		return
+	}
+	if string(p.Request().RequestTarget) != "/2" {
+		t.Errorf("expected: /2, actual: %s", p.Request().RequestTarget)
+	}
+
+	p.Reset()
+	err = p.Finish()
+	if err != io.EOF {
+		t.Errorf("expected: %v, actual: %v", io.EOF, err)
+	}
+}
+
+func TestResponseParserFeed(t *testing.T) {
+	type TestCaseForResponseParserFeed struct {
+		testName               string
+		chunks                 [][]byte
+		finish                 bool
+		err                    bool
+		expectedStatusCode     []byte
+		expectedMessageBody    []byte
+		expectedCloseDelimited bool
+	}
+
+	tests := []TestCaseForResponseParserFeed{
+		{
+			testName: "Content-Length",
+			chunks: [][]byte{
+				[]byte("HTTP/1.1 200 OK\r\nContent-Length: 7\r\n\r\nabc"),
+				[]byte("defg"),
+			},
+			finish:              false,
+			err:                 false,
+			expectedStatusCode:  []byte("200"),
+			expectedMessageBody: []byte("abcdefg"),
+		},
+		{
+			testName: "delimited by the closing of the connection",
+			chunks: [][]byte{
+				[]byte("HTTP/1.1 200 OK\r\n\r\nabc"),
+				[]byte("defg"),
+			},
+			finish:                 true,
+			err:                    false,
+			expectedStatusCode:     []byte("200"),
+			expectedMessageBody:    []byte("abcdefg"),
+			expectedCloseDelimited: true,
+		},
+		{
+			testName: "incomplete",
+			chunks: [][]byte{
+				[]byte("HTTP/1.1 200 OK\r\nContent-Length: 7\r\n\r\nabc"),
+			},
+			finish: true,
+			err:    true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			p := NewResponseParser()
+			var err error
+			for _, chunk := range testCase.chunks {
+				err = p.Feed(chunk)
+			}
+			if testCase.finish || err == ErrNeedMoreData {
+				err = p.Finish()
+			}
+			if err != nil {
+				if !testCase.err {
+					t.Errorf("Failed to parse Http/1.1 Response: %v", err.Error())
+				}
+				return
+			}
+			if testCase.err {
+				t.Errorf("Unexpectedly parse Http/1.1 Response successfully: %v", p.Response())
+				return
+			}
+			resp := p.Response()
+			if !byteEquals(testCase.expectedStatusCode, resp.StatusCode) {
+				t.Errorf("expectedStatusCode: %s, actual: %s", testCase.expectedStatusCode, resp.StatusCode)
+			}
+			if !byteEquals(testCase.expectedMessageBody, resp.MessageBody) {
+				t.Errorf("expectedMessageBody: %s, actual: %s", testCase.expectedMessageBody, resp.MessageBody)
+			}
+			equals(testCase.testName, t, testCase.expectedCloseDelimited, resp.CloseDelimited)
+		})
+	}
+}
+
+func TestNewResponseParserForRequest(t *testing.T) {
+	p := NewResponseParserForRequest([]byte("CONNECT"))
+	err := p.Feed([]byte(
+		"HTTP/1.1 200 Connection Established\r\n" +
+			"\r\n" +
+			"\x16\x03\x01",
+	))
+	if err != nil {
+		t.Errorf("Failed to parse Http/1.1 Response: %v", err.Error())
+		return
+	}
+	if !byteEquals([]byte{}, p.Response().MessageBody) {
+		t.Errorf("expectedMessageBody: [], actual: %s", p.Response().MessageBody)
+	}
+	if !byteEquals([]byte("\x16\x03\x01"), p.Remaining()) {
+		t.Errorf("expectedRemaining: \\x16\\x03\\x01, actual: %s", p.Remaining())
+	}
+}
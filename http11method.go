@@ -0,0 +1,176 @@
+package http11p
+
+import (
+	"sync"
+)
+
+// RFC9110 - 9.1. Overview
+//
+// The method token is case-sensitive because it might be used as a gateway
+// to object-based systems with case-sensitive method names. By convention,
+// standardized methods are defined in all-uppercase US-ASCII letters.
+//
+// RFC9110 - 9.2.1. Safe Methods
+//
+// Request methods are considered "safe" if their defined semantics are
+// essentially read-only. Of the request methods defined by this
+// specification, the GET, HEAD, OPTIONS, and TRACE methods are defined to be
+// safe.
+//
+// RFC9110 - 9.2.2. Idempotent Methods
+//
+// A request method is considered "idempotent" if the intended effect on the
+// server of multiple identical requests with that method is the same as the
+// effect for a single such request. Of the request methods defined by this
+// specification, PUT, DELETE, and safe request methods are idempotent.
+//
+// RFC9110 - 9.2.3. Methods and Caching
+//
+// For a cache to store and use a response, the associated method needs to
+// explicitly allow caching and to detail under what conditions a response
+// can be used to satisfy subsequent requests. This specification defines
+// caching semantics for GET, HEAD, and POST.
+//
+
+// Method is a method token. Convert Http11Request.Method with
+// Method(req.Method) to look up its properties.
+type Method string
+
+// The methods in the IANA HTTP Method Registry.
+const (
+	MethodAcl               Method = "ACL"
+	MethodBaselineControl   Method = "BASELINE-CONTROL"
+	MethodBind              Method = "BIND"
+	MethodCheckin           Method = "CHECKIN"
+	MethodCheckout          Method = "CHECKOUT"
+	MethodConnect           Method = "CONNECT"
+	MethodCopy              Method = "COPY"
+	MethodDelete            Method = "DELETE"
+	MethodGet               Method = "GET"
+	MethodHead              Method = "HEAD"
+	MethodLabel             Method = "LABEL"
+	MethodLink              Method = "LINK"
+	MethodLock              Method = "LOCK"
+	MethodMerge             Method = "MERGE"
+	MethodMkactivity        Method = "MKACTIVITY"
+	MethodMkcalendar        Method = "MKCALENDAR"
+	MethodMkcol             Method = "MKCOL"
+	MethodMkredirectref     Method = "MKREDIRECTREF"
+	MethodMkworkspace       Method = "MKWORKSPACE"
+	MethodMove              Method = "MOVE"
+	MethodOptions           Method = "OPTIONS"
+	MethodOrderpatch        Method = "ORDERPATCH"
+	MethodPatch             Method = "PATCH"
+	MethodPost              Method = "POST"
+	MethodPri               Method = "PRI"
+	MethodPropfind          Method = "PROPFIND"
+	MethodProppatch         Method = "PROPPATCH"
+	MethodPut               Method = "PUT"
+	MethodRebind            Method = "REBIND"
+	MethodReport            Method = "REPORT"
+	MethodSearch            Method = "SEARCH"
+	MethodTrace             Method = "TRACE"
+	MethodUnbind            Method = "UNBIND"
+	MethodUncheckout        Method = "UNCHECKOUT"
+	MethodUnlink            Method = "UNLINK"
+	MethodUnlock            Method = "UNLOCK"
+	MethodUpdate            Method = "UPDATE"
+	MethodUpdateredirectref Method = "UPDATEREDIRECTREF"
+	MethodVersionControl    Method = "VERSION-CONTROL"
+)
+
+// MethodProperties is the properties of a method.
+type MethodProperties struct {
+	Safe       bool
+	Idempotent bool
+	Cacheable  bool
+}
+
+var (
+	methodsMutex sync.RWMutex
+	methods      = map[Method]MethodProperties{
+		MethodAcl:               {Idempotent: true},
+		MethodBaselineControl:   {Idempotent: true},
+		MethodBind:              {Idempotent: true},
+		MethodCheckin:           {Idempotent: true},
+		MethodCheckout:          {Idempotent: true},
+		MethodConnect:           {},
+		MethodCopy:              {Idempotent: true},
+		MethodDelete:            {Idempotent: true},
+		MethodGet:               {Safe: true, Idempotent: true, Cacheable: true},
+		MethodHead:              {Safe: true, Idempotent: true, Cacheable: true},
+		MethodLabel:             {Idempotent: true},
+		MethodLink:              {Idempotent: true},
+		MethodLock:              {},
+		MethodMerge:             {Idempotent: true},
+		MethodMkactivity:        {Idempotent: true},
+		MethodMkcalendar:        {Idempotent: true},
+		MethodMkcol:             {Idempotent: true},
+		MethodMkredirectref:     {Idempotent: true},
+		MethodMkworkspace:       {Idempotent: true},
+		MethodMove:              {Idempotent: true},
+		MethodOptions:           {Safe: true, Idempotent: true},
+		MethodOrderpatch:        {Idempotent: true},
+		MethodPatch:             {},
+		MethodPost:              {Cacheable: true},
+		MethodPri:               {Safe: true, Idempotent: true},
+		MethodPropfind:          {Safe: true, Idempotent: true},
+		MethodProppatch:         {Idempotent: true},
+		MethodPut:               {Idempotent: true},
+		MethodRebind:            {Idempotent: true},
+		MethodReport:            {Safe: true, Idempotent: true},
+		MethodSearch:            {Safe: true, Idempotent: true},
+		MethodTrace:             {Safe: true, Idempotent: true},
+		MethodUnbind:            {Idempotent: true},
+		MethodUncheckout:        {Idempotent: true},
+		MethodUnlink:            {Idempotent: true},
+		MethodUnlock:            {Idempotent: true},
+		MethodUpdate:            {Idempotent: true},
+		MethodUpdateredirectref: {Idempotent: true},
+		MethodVersionControl:    {Idempotent: true},
+	}
+)
+
+// RegisterMethod registers method with its properties, or replaces the
+// properties of a registered method. It panics if method is not a token.
+func RegisterMethod(method Method, properties MethodProperties) {
+	if !matchesAll([]byte(method), NewMethodFinder()) {
+		panic("http11p: method is not a token: " + string(method))
+	}
+	methodsMutex.Lock()
+	defer methodsMutex.Unlock()
+	methods[method] = properties
+}
+
+// Properties returns the properties of method, and false if method is not
+// registered.
+func (method Method) Properties() (properties MethodProperties, ok bool) {
+	methodsMutex.RLock()
+	defer methodsMutex.RUnlock()
+	properties, ok = methods[method]
+	return
+}
+
+// IsRegistered reports whether method is registered.
+func (method Method) IsRegistered() bool {
+	_, ok := method.Properties()
+	return ok
+}
+
+// IsSafe reports whether method is registered as safe.
+func (method Method) IsSafe() bool {
+	properties, _ := method.Properties()
+	return properties.Safe
+}
+
+// IsIdempotent reports whether method is registered as idempotent.
+func (method Method) IsIdempotent() bool {
+	properties, _ := method.Properties()
+	return properties.Idempotent
+}
+
+// IsCacheable reports whether method is registered as cacheable.
+func (method Method) IsCacheable() bool {
+	properties, _ := method.Properties()
+	return properties.Cacheable
+}
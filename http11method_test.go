@@ -0,0 +1,76 @@
+package http11p
+
+import (
+	"testing"
+)
+
+func TestMethodProperties(t *testing.T) {
+	tests := []struct {
+		method             Method
+		expectedRegistered bool
+		expectedSafe       bool
+		expectedIdempotent bool
+		expectedCacheable  bool
+	}{
+		{
+			method:             MethodGet,
+			expectedRegistered: true,
+			expectedSafe:       true,
+			expectedIdempotent: true,
+			expectedCacheable:  true,
+		},
+		{
+			method:             MethodPost,
+			expectedRegistered: true,
+			expectedCacheable:  true,
+		},
+		{
+			method:             MethodPut,
+			expectedRegistered: true,
+			expectedIdempotent: true,
+		},
+		{
+			method:             MethodPatch,
+			expectedRegistered: true,
+		},
+		{
+			method:             MethodPropfind,
+			expectedRegistered: true,
+			expectedSafe:       true,
+			expectedIdempotent: true,
+		},
+		{
+			// Methods are case-sensitive.
+			method: "get",
+		},
+		{
+			method: "FOO",
+		},
+	}
+
+	for _, test := range tests {
+		name := string(test.method)
+		equals(name+" IsRegistered", t, test.expectedRegistered, test.method.IsRegistered())
+		equals(name+" IsSafe", t, test.expectedSafe, test.method.IsSafe())
+		equals(name+" IsIdempotent", t, test.expectedIdempotent, test.method.IsIdempotent())
+		equals(name+" IsCacheable", t, test.expectedCacheable, test.method.IsCacheable())
+	}
+}
+
+func TestRegisterMethod(t *testing.T) {
+	method := Method("X-REPLAY")
+	RegisterMethod(method, MethodProperties{Idempotent: true})
+	equals("IsRegistered", t, true, method.IsRegistered())
+	equals("IsSafe", t, false, method.IsSafe())
+	equals("IsIdempotent", t, true, method.IsIdempotent())
+
+	req := Http11Request{Method: []byte("X-REPLAY")}
+	equals("Method(req.Method)", t, true, Method(req.Method).IsIdempotent())
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterMethod did not panic with a method which is not a token")
+		}
+	}()
+	RegisterMethod(Method("BAD METHOD"), MethodProperties{})
+}
@@ -0,0 +1,249 @@
+package http11p
+
+import (
+	"bytes"
+	"math"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC9112 - 3. Request Line
+//
+// HTTP does not place a predefined limit on the length of a request-line,
+// as described in Section 2.3 of [HTTP]. A server that receives a method
+// longer than any that it implements SHOULD respond with a 501 (Not
+// Implemented) status code. A server that receives a request-target longer
+// than any URI it wishes to parse MUST respond with a 414 (URI Too Long)
+// status code (see Section 15.5.15 of [HTTP]).
+//
+// RFC6585 - 5. 431 Request Header Fields Too Large
+//
+// The 431 status code indicates that the server is unwilling to process the
+// request because its header fields are too large. The request MAY be
+// resubmitted after reducing the size of the request header fields.
+//
+// RFC9110 - 15.5.14. 413 Content Too Large
+//
+// The 413 (Content Too Large) status code indicates that the server is
+// refusing to process a request because the request content is larger than
+// the server is willing or able to process.
+//
+
+// ParserOptions configures the parsers. The lengths and sizes are in bytes
+// and include the line terminators. A zero value means no limit.
+type ParserOptions struct {
+	// MaxStartLineLength limits the length of the request-line or the
+	// status-line.
+	MaxStartLineLength int
+
+	// MaxFieldLineLength limits the length of each field line.
+	MaxFieldLineLength int
+
+	// MaxFieldCount limits the number of field lines in the header section
+	// and in the trailer section.
+	MaxFieldCount int
+
+	// MaxHeaderSectionSize limits the total length of the field lines in the
+	// header section and in the trailer section.
+	MaxHeaderSectionSize int
+
+	// MaxBodySize limits the length of the message-body after decoding the
+	// chunked transfer coding.
+	MaxBodySize int64
+
+	// MaxChunkSizeLineLength limits the length of each line of chunk-size,
+	// chunk-ext and CRLF in the chunked transfer coding, including
+	// last-chunk.
+	MaxChunkSizeLineLength int
+
+	// Lenient enables the robustness allowances of RFC9112: a bare LF is
+	// accepted as a line terminator, and empty lines before the start-line
+	// are ignored.
+	Lenient bool
+
+	// ObsFold is how a field value containing obs-fold is handled.
+	ObsFold ObsFoldPolicy
+}
+
+// RFC9112 - 5.2. Obsolete Line Folding
+//
+// A server that receives an obs-fold in a request message that is not
+// within a "message/http" container MUST either reject the message by
+// sending a 400 (Bad Request), preferably with a representation explaining
+// that obsolete line folding is unacceptable, or replace each received
+// obs-fold with one or more SP octets prior to interpreting the field value
+// or forwarding the message downstream.
+//
+// A user agent that receives an obs-fold in a response message that is not
+// within a "message/http" container MUST replace each received obs-fold
+// with one or more SP octets prior to interpreting the field value.
+//
+
+type ObsFoldPolicy int
+
+const (
+	// ObsFoldReject rejects a message containing obs-fold with ErrObsFold.
+	ObsFoldReject ObsFoldPolicy = iota
+
+	// ObsFoldUnfold replaces each obs-fold with a SP.
+	ObsFoldUnfold
+)
+
+// RFC9112 - 2.2. Message Parsing
+//
+// Although the line terminator for the start-line and fields is the
+// sequence CRLF, a recipient MAY recognize a single LF as a line terminator
+// and ignore any preceding CR.
+//
+// In the interest of robustness, a server that is expecting to receive and
+// parse a request-line SHOULD ignore at least one empty line (CRLF)
+// received prior to the request-line.
+//
+
+// newEolFinder returns the Finder of a line terminator, which is CRLF, or
+// [ CR ] LF if Lenient.
+func (options ParserOptions) newEolFinder() abnfp.Finder {
+	if !options.Lenient {
+		return abnfp.NewCrLfFinder()
+	}
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(abnfp.NewByteFinder('\r')),
+		abnfp.NewByteFinder('\n'),
+	})
+}
+
+// newObsFoldFinder returns the Finder of obs-fold, whose CRLF can be a bare
+// LF if Lenient.
+func (options ParserOptions) newObsFoldFinder() abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewOwsFinder(),
+		options.newEolFinder(),
+		NewRwsFinder(),
+	})
+}
+
+// skipEmptyLines returns data after the empty lines at its start if
+// Lenient.
+func (options ParserOptions) skipEmptyLines(data []byte) []byte {
+	if !options.Lenient {
+		return data
+	}
+	_, data = abnfp.Parse(data, abnfp.NewVariableRepetitionFinder(options.newEolFinder()))
+	return data
+}
+
+// DefaultParserOptions returns the options used by the functions which do
+// not take ParserOptions.
+func DefaultParserOptions() ParserOptions {
+	return ParserOptions{
+		MaxStartLineLength:     8 * 1024,
+		MaxFieldLineLength:     8 * 1024,
+		MaxFieldCount:          100,
+		MaxHeaderSectionSize:   64 * 1024,
+		MaxChunkSizeLineLength: 8 * 1024,
+	}
+}
+
+// checkStartLine returns an error if the line at the start of data, whose
+// length is n bytes so far, is longer than MaxStartLineLength.
+func (options ParserOptions) checkStartLine(n int, data []byte) error {
+	if options.MaxStartLineLength > 0 && n > options.MaxStartLineLength {
+		return newParseError(ErrStartLineTooLong, "start-line", "start-line too long", data)
+	}
+	return nil
+}
+
+// checkRequestLine is checkStartLine for a request-line. If the method does
+// not end within MaxStartLineLength, the error is located at the method, for
+// which a server responds 501 instead of 414.
+func (options ParserOptions) checkRequestLine(n int, data []byte) error {
+	err := options.checkStartLine(n, data)
+	if err != nil && bytes.IndexByte(data[:options.MaxStartLineLength], ' ') < 0 {
+		return newParseError(ErrStartLineTooLong, "method", "method too long", data)
+	}
+	return err
+}
+
+// checkChunkSizeLine returns an error if the line at the start of data,
+// whose length is n bytes so far, is longer than MaxChunkSizeLineLength.
+func (options ParserOptions) checkChunkSizeLine(n int, data []byte) error {
+	if options.MaxChunkSizeLineLength > 0 && n > options.MaxChunkSizeLineLength {
+		return newParseError(ErrChunkSizeLineTooLong, "chunk-size", "chunk-size line too long", data)
+	}
+	return nil
+}
+
+// checkBodySize returns an error if a message-body of size bytes, which
+// starts at data, is larger than MaxBodySize.
+func (options ParserOptions) checkBodySize(size int64, data []byte) error {
+	if options.MaxBodySize > 0 && size > options.MaxBodySize {
+		return newParseError(ErrBodyTooLarge, "message-body", "message-body too large", data)
+	}
+	return nil
+}
+
+// addBodySize returns size+n, or the maximum of int64 if it overflows, so
+// that checkBodySize rejects a chunk-size close to the maximum.
+func addBodySize(size int64, n int64) int64 {
+	if size > math.MaxInt64-n {
+		return math.MaxInt64
+	}
+	return size + n
+}
+
+// fieldSectionLimiter checks the field lines of a header section or a
+// trailer section against the options.
+type fieldSectionLimiter struct {
+	options ParserOptions
+	count   int
+	size    int
+}
+
+// check returns an error if the line at the start of data, whose length is
+// n bytes so far, cannot be added to the field section.
+func (limiter *fieldSectionLimiter) check(n int, data []byte) error {
+	options := limiter.options
+	if options.MaxFieldLineLength > 0 && n > options.MaxFieldLineLength {
+		return newParseError(ErrFieldLineTooLong, "field-line", "field-line too long", data)
+	}
+	if options.MaxHeaderSectionSize > 0 && limiter.size+n > options.MaxHeaderSectionSize {
+		return newParseError(ErrHeaderSectionTooLarge, "field-line", "field section too large", data)
+	}
+	return nil
+}
+
+// add adds a field line of n bytes to the field section.
+func (limiter *fieldSectionLimiter) add(n int, data []byte) error {
+	err := limiter.check(n, data)
+	if err != nil {
+		return err
+	}
+	if limiter.options.MaxFieldCount > 0 && limiter.count+1 > limiter.options.MaxFieldCount {
+		return newParseError(ErrTooManyFieldLines, "field-line", "too many field lines", data)
+	}
+	limiter.count++
+	limiter.size += n
+	return nil
+}
+
+// extend adds a line of n bytes, which continues the last field line with
+// obs-fold, to the field section.
+func (limiter *fieldSectionLimiter) extend(n int, data []byte) error {
+	err := limiter.check(n, data)
+	if err != nil {
+		return err
+	}
+	limiter.size += n
+	return nil
+}
+
+// lineLength returns the length of the line at the start of data including
+// its line terminator, or the length of data if it does not contain a whole
+// line.
+func lineLength(data []byte) int {
+	i := bytes.IndexByte(data, '\n')
+	if i < 0 {
+		return len(data)
+	}
+	return i + 1
+}
@@ -0,0 +1,488 @@
+package http11p
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type TestCaseForParserOptions struct {
+	testName           string
+	data               []byte
+	options            ParserOptions
+	expectedErr        error
+	expectedStatusCode int
+}
+
+func execTestForParserOptions(testName string, t *testing.T, test TestCaseForParserOptions, err error) {
+	if test.expectedErr == nil {
+		if err != nil {
+			t.Errorf("%v: expected: no error, actual: %v", testName, err)
+		}
+		return
+	}
+	if !errors.Is(err, test.expectedErr) {
+		t.Errorf("%v: expected: %v, actual: %v", testName, test.expectedErr, err)
+		return
+	}
+	equals(testName+" StatusCodeForError", t, test.expectedStatusCode, StatusCodeForError(err))
+}
+
+func TestRequestParserOptions(t *testing.T) {
+	options := ParserOptions{
+		MaxStartLineLength:     32,
+		MaxFieldLineLength:     32,
+		MaxFieldCount:          2,
+		MaxHeaderSectionSize:   40,
+		MaxBodySize:            4,
+		MaxChunkSizeLineLength: 16,
+	}
+	tests := []TestCaseForParserOptions{
+		{
+			testName:    "within the limits",
+			data:        []byte("POST / HTTP/1.1\r\nA: 1\r\nContent-Length: 4\r\n\r\nabcd"),
+			options:     options,
+			expectedErr: nil,
+		},
+		{
+			testName:           "long request-line",
+			data:               []byte("GET /" + strings.Repeat("a", 32) + " HTTP/1.1\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrStartLineTooLong,
+			expectedStatusCode: 414,
+		},
+		{
+			testName:           "long method",
+			data:               []byte(strings.Repeat("A", 33) + " / HTTP/1.1\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrStartLineTooLong,
+			expectedStatusCode: 501,
+		},
+		{
+			testName:           "long field-line",
+			data:               []byte("GET / HTTP/1.1\r\nCookie: " + strings.Repeat("a", 32) + "\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrFieldLineTooLong,
+			expectedStatusCode: 431,
+		},
+		{
+			testName:           "too many field lines",
+			data:               []byte("GET / HTTP/1.1\r\nA: 1\r\nB: 2\r\nC: 3\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrTooManyFieldLines,
+			expectedStatusCode: 431,
+		},
+		{
+			testName:           "large header section",
+			data:               []byte("GET / HTTP/1.1\r\nA: " + strings.Repeat("1", 16) + "\r\nB: " + strings.Repeat("2", 16) + "\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrHeaderSectionTooLarge,
+			expectedStatusCode: 431,
+		},
+		{
+			testName:           "large Content-Length",
+			data:               []byte("POST / HTTP/1.1\r\nContent-Length: 5\r\n\r\nabcde"),
+			options:            options,
+			expectedErr:        ErrBodyTooLarge,
+			expectedStatusCode: 413,
+		},
+		{
+			testName:           "large chunked-body",
+			data:               []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc\r\n2\r\nde\r\n0\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrBodyTooLarge,
+			expectedStatusCode: 413,
+		},
+		{
+			testName:           "long chunk-ext",
+			data:               []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n1;" + strings.Repeat("a", 16) + "\r\na\r\n0\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrChunkSizeLineTooLong,
+			expectedStatusCode: 400,
+		},
+		{
+			testName:           "long last-chunk",
+			data:               []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n0;" + strings.Repeat("a", 16) + "\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrChunkSizeLineTooLong,
+			expectedStatusCode: 400,
+		},
+		{
+			testName:           "large trailer section",
+			data:               []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n0\r\nA: 1\r\nB: 2\r\nC: 3\r\n\r\n"),
+			options:            options,
+			expectedErr:        ErrTooManyFieldLines,
+			expectedStatusCode: 431,
+		},
+		{
+			testName:    "no limits",
+			data:        []byte("GET /" + strings.Repeat("a", 32) + " HTTP/1.1\r\nA: 1\r\nB: 2\r\nC: 3\r\n\r\n"),
+			options:     ParserOptions{},
+			expectedErr: nil,
+		},
+	}
+
+	for _, test := range tests {
+		var req Http11Request
+		_, err := req.MarshalWithOptions(test.data, test.options)
+		execTestForParserOptions(test.testName+" (Marshal)", t, test, err)
+
+		p := NewRequestParserWithOptions(test.options)
+		err = p.Feed(test.data)
+		execTestForParserOptions(test.testName+" (RequestParser)", t, test, err)
+
+		reader := bufio.NewReader(bytes.NewReader(test.data))
+		readReq, err := ReadRequestWithOptions(reader, test.options)
+		if err == nil {
+			_, err = io.ReadAll(readReq.Body)
+		}
+		execTestForParserOptions(test.testName+" (ReadRequest)", t, test, err)
+	}
+}
+
+func TestResponseParserOptions(t *testing.T) {
+	options := ParserOptions{MaxBodySize: 4}
+	data := []byte("HTTP/1.1 200 OK\r\n\r\nabcde")
+	test := TestCaseForParserOptions{
+		data:               data,
+		options:            options,
+		expectedErr:        ErrBodyTooLarge,
+		expectedStatusCode: 413,
+	}
+
+	var resp Http11Response
+	_, err := resp.MarshalWithOptions(data, nil, options)
+	execTestForParserOptions("Marshal", t, test, err)
+
+	p := NewResponseParserWithOptions(nil, options)
+	err = p.Feed(data)
+	execTestForParserOptions("ResponseParser", t, test, err)
+
+	readResp, err := ReadResponseWithOptions(bufio.NewReader(bytes.NewReader(data)), nil, options)
+	if err == nil {
+		_, err = io.ReadAll(readResp.Body)
+	}
+	execTestForParserOptions("ReadResponse", t, test, err)
+}
+
+// The bytes beyond MaxBodySize are not returned by Body with
+// ErrBodyTooLarge.
+func TestReadRequestBodyWithinMaxBodySize(t *testing.T) {
+	data := []byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nabc\r\n3\r\nabc\r\n0\r\n\r\n")
+	req, err := ReadRequestWithOptions(bufio.NewReader(bytes.NewReader(data)), ParserOptions{MaxBodySize: 4})
+	if err != nil {
+		t.Fatalf("expected: no error, actual: %v", err)
+	}
+	body, err := io.ReadAll(req.Body)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("expected: %v, actual: %v", ErrBodyTooLarge, err)
+	}
+	equals("Body", t, "abca", string(body))
+
+	n, err := req.Body.Read(make([]byte, 8))
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("expected: %v, actual: %v", ErrBodyTooLarge, err)
+	}
+	equals("Read after ErrBodyTooLarge", t, 0, n)
+}
+
+// The line is rejected before it is complete, so that the parser does not
+// buffer an unbounded line.
+func TestRequestParserRejectsIncompleteLongLine(t *testing.T) {
+	p := NewRequestParserWithOptions(ParserOptions{MaxStartLineLength: 16})
+	err := p.Feed([]byte("GET /aaaa"))
+	if err != ErrNeedMoreData {
+		t.Errorf("expected: %v, actual: %v", ErrNeedMoreData, err)
+	}
+	err = p.Feed([]byte("aaaaaaaaaa"))
+	if !errors.Is(err, ErrStartLineTooLong) {
+		t.Errorf("expected: %v, actual: %v", ErrStartLineTooLong, err)
+	}
+}
+
+func TestRequestParserRejectsIncompleteLongChunkSizeLine(t *testing.T) {
+	p := NewRequestParser()
+	err := p.Feed([]byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n1;"))
+	if err != ErrNeedMoreData {
+		t.Errorf("expected: %v, actual: %v", ErrNeedMoreData, err)
+	}
+	err = p.Feed([]byte(strings.Repeat("a", 8*1024)))
+	if !errors.Is(err, ErrChunkSizeLineTooLong) {
+		t.Errorf("expected: %v, actual: %v", ErrChunkSizeLineTooLong, err)
+	}
+}
+
+// The CR of the empty line at the end of a field section is not counted
+// against the limits when its LF arrives in the next chunk.
+func TestRequestParserAcceptsSplitEmptyLine(t *testing.T) {
+	tests := []struct {
+		testName string
+		chunks   [][]byte
+	}{
+		{
+			testName: "header section",
+			chunks: [][]byte{
+				[]byte("GET / HTTP/1.1\r\nA: " + strings.Repeat("a", 23) + "\r\n\r"),
+				[]byte("\n"),
+			},
+		},
+		{
+			testName: "trailer section",
+			chunks: [][]byte{
+				[]byte("POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n0\r\nA: " + strings.Repeat("a", 23) + "\r\n\r"),
+				[]byte("\n"),
+			},
+		},
+	}
+	options := ParserOptions{MaxFieldLineLength: 28, MaxHeaderSectionSize: 28}
+
+	for _, test := range tests {
+		p := NewRequestParserWithOptions(options)
+		err := p.Feed(test.chunks[0])
+		if err != ErrNeedMoreData {
+			t.Errorf("%v: expected: %v, actual: %v", test.testName, ErrNeedMoreData, err)
+			continue
+		}
+		err = p.Feed(test.chunks[1])
+		if err != nil {
+			t.Errorf("%v: expected: no error, actual: %v", test.testName, err)
+		}
+	}
+}
+
+// Once Feed fails, it returns the same error until Reset, even if the
+// following data could be parsed.
+func TestRequestParserErrorIsSticky(t *testing.T) {
+	p := NewRequestParser()
+	err := p.Feed([]byte("GET  / HTTP/1.1\r\n"))
+	if !errors.Is(err, ErrInvalidRequestLine) {
+		t.Errorf("expected: %v, actual: %v", ErrInvalidRequestLine, err)
+	}
+	if again := p.Feed([]byte("GET / HTTP/1.1\r\n\r\n")); again != err {
+		t.Errorf("Feed after error: expected: %v, actual: %v", err, again)
+	}
+	if again := p.Finish(); again != err {
+		t.Errorf("Finish after error: expected: %v, actual: %v", err, again)
+	}
+
+	p.Reset()
+	err = p.Feed([]byte("GET / HTTP/1.1\r\n\r\n"))
+	equals("Feed after Reset", t, true, err == nil)
+}
+
+func TestDefaultParserOptions(t *testing.T) {
+	data := []byte("GET / HTTP/1.1\r\n" + strings.Repeat("A: 1\r\n", 101) + "\r\n")
+	var req Http11Request
+	err := req.Marshal(data)
+	if !errors.Is(err, ErrTooManyFieldLines) {
+		t.Errorf("expected: %v, actual: %v", ErrTooManyFieldLines, err)
+	}
+	equals("StatusCodeForError(nil)", t, 0, StatusCodeForError(nil))
+	equals("StatusCodeForError(ErrInvalidFieldLine)", t, 400, StatusCodeForError(newParseError(ErrInvalidFieldLine, "", "", nil)))
+}
+
+func TestLenientParserOptions(t *testing.T) {
+	tests := []struct {
+		testName            string
+		data                []byte
+		expectedMethod      []byte
+		expectedFieldValue  []byte
+		expectedMessageBody []byte
+	}{
+		{
+			testName:            "bare LF",
+			data:                []byte("POST / HTTP/1.1\nHost: a\nContent-Length: 3\n\nabc"),
+			expectedMethod:      []byte("POST"),
+			expectedFieldValue:  []byte("a"),
+			expectedMessageBody: []byte("abc"),
+		},
+		{
+			testName:            "empty lines before request-line",
+			data:                []byte("\r\n\nGET / HTTP/1.1\r\nHost: a\r\n\r\n"),
+			expectedMethod:      []byte("GET"),
+			expectedFieldValue:  []byte("a"),
+			expectedMessageBody: []byte{},
+		},
+		{
+			testName:            "bare LF in chunked-body",
+			data:                []byte("POST / HTTP/1.1\nHost: a\nTransfer-Encoding: chunked\n\n3\nabc\n0\nExpires: 0\n\n"),
+			expectedMethod:      []byte("POST"),
+			expectedFieldValue:  []byte("a"),
+			expectedMessageBody: []byte("abc"),
+		},
+	}
+	options := DefaultParserOptions()
+	options.Lenient = true
+
+	for _, test := range tests {
+		var strictReq Http11Request
+		err := strictReq.Marshal(test.data)
+		if err == nil {
+			t.Errorf("%v (strict): expected: error, actual: no error", test.testName)
+		}
+
+		reqs := []*Http11Request{}
+		var req Http11Request
+		_, err = req.MarshalWithOptions(test.data, options)
+		if err != nil {
+			t.Errorf("%v (Marshal): %v", test.testName, err)
+			continue
+		}
+		reqs = append(reqs, &req)
+
+		p := NewRequestParserWithOptions(options)
+		err = p.Feed(test.data)
+		if err != nil {
+			t.Errorf("%v (RequestParser): %v", test.testName, err)
+			continue
+		}
+		reqs = append(reqs, p.Request())
+
+		readReq, err := ReadRequestWithOptions(bufio.NewReader(bytes.NewReader(test.data)), options)
+		if err != nil {
+			t.Errorf("%v (ReadRequest): %v", test.testName, err)
+			continue
+		}
+		readReq.MessageBody, err = io.ReadAll(readReq.Body)
+		if err != nil {
+			t.Errorf("%v (ReadRequest): %v", test.testName, err)
+			continue
+		}
+		reqs = append(reqs, readReq)
+
+		for _, actual := range reqs {
+			if !byteEquals(test.expectedMethod, actual.Method) {
+				t.Errorf("%v: expected: %s, actual: %s", test.testName, test.expectedMethod, actual.Method)
+			}
+			if !byteEquals(test.expectedFieldValue, actual.GetHeader("Host")) {
+				t.Errorf("%v: expected: %s, actual: %s", test.testName, test.expectedFieldValue, actual.GetHeader("Host"))
+			}
+			if !byteEquals(test.expectedMessageBody, actual.MessageBody) {
+				t.Errorf("%v: expected: %s, actual: %s", test.testName, test.expectedMessageBody, actual.MessageBody)
+			}
+		}
+	}
+}
+
+func TestLenientResponse(t *testing.T) {
+	options := DefaultParserOptions()
+	options.Lenient = true
+	data := []byte("\nHTTP/1.1 200 OK\nContent-Length: 2\n\nok")
+
+	var resp Http11Response
+	_, err := resp.MarshalWithOptions(data, nil, options)
+	if err != nil {
+		t.Errorf("Failed to marshal Http/1.1 Response: %v", err.Error())
+		return
+	}
+	equals("StatusCode", t, "200", string(resp.StatusCode))
+	equals("ReasonPhrase", t, "OK", string(resp.ReasonPhrase))
+	equals("MessageBody", t, "ok", string(resp.MessageBody))
+}
+
+func TestObsFoldParserOptions(t *testing.T) {
+	data := []byte("POST / HTTP/1.1\r\nX-Long: a\r\n b \r\n\tc\r\nTransfer-Encoding: chunked\r\n\r\n" +
+		"0\r\nX-Trailer: d\r\n  e\r\n\r\n")
+
+	// ObsFoldReject
+	test := TestCaseForParseError{
+		testName:       "ObsFoldReject",
+		data:           data,
+		expectedErr:    ErrObsFold,
+		expectedRule:   "obs-fold",
+		expectedOffset: 28,
+		expectedLine:   3,
+		expectedColumn: 1,
+	}
+	var req Http11Request
+	err := req.Marshal(data)
+	execTestForParseError(test.testName+" (Marshal)", t, test, err)
+	err = NewRequestParser().Feed(data)
+	execTestForParseError(test.testName+" (RequestParser)", t, test, err)
+	_, err = ReadRequest(bufio.NewReader(bytes.NewReader(data)))
+	execTestForParseError(test.testName+" (ReadRequest)", t, test, err)
+
+	// ObsFoldUnfold
+	options := DefaultParserOptions()
+	options.ObsFold = ObsFoldUnfold
+	reqs := []*Http11Request{}
+
+	var marshaledReq Http11Request
+	_, err = marshaledReq.MarshalWithOptions(data, options)
+	if err != nil {
+		t.Errorf("ObsFoldUnfold (Marshal): %v", err)
+		return
+	}
+	reqs = append(reqs, &marshaledReq)
+
+	p := NewRequestParserWithOptions(options)
+	err = p.Feed(data)
+	if err != nil {
+		t.Errorf("ObsFoldUnfold (RequestParser): %v", err)
+		return
+	}
+	reqs = append(reqs, p.Request())
+
+	readReq, err := ReadRequestWithOptions(bufio.NewReader(bytes.NewReader(data)), options)
+	if err == nil {
+		_, err = io.ReadAll(readReq.Body)
+	}
+	if err != nil {
+		t.Errorf("ObsFoldUnfold (ReadRequest): %v", err)
+		return
+	}
+	reqs = append(reqs, readReq)
+
+	for _, actual := range reqs {
+		equals("X-Long", t, "a b c", string(actual.FieldLines.Get("X-Long")))
+		equals("Transfer-Encoding", t, "chunked", string(actual.FieldLines.Get("Transfer-Encoding")))
+		equals("X-Trailer", t, "d e", string(actual.TrailerSection.Get("X-Trailer")))
+	}
+
+	// An empty field-value continued with obs-fold.
+	tests := []struct {
+		testName    string
+		data        []byte
+		expectedErr error
+		expected    string
+	}{
+		{
+			testName: "empty field-value continued with obs-fold",
+			data:     []byte("GET / HTTP/1.1\r\nX:\r\n b\r\n\r\n"),
+			expected: "b",
+		},
+		{
+			testName: "empty field-value",
+			data:     []byte("GET / HTTP/1.1\r\nX:\r\nY: a\r\n\r\n"),
+			expected: "",
+		},
+		{
+			testName: "empty field-value before the empty line",
+			data:     []byte("GET / HTTP/1.1\r\nX: \r\n\r\n"),
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		var marshaledReq Http11Request
+		_, marshalErr := marshaledReq.MarshalWithOptions(test.data, options)
+		p := NewRequestParserWithOptions(options)
+		feedErr := p.Feed(test.data)
+		readReq, readErr := ReadRequestWithOptions(bufio.NewReader(bytes.NewReader(test.data)), options)
+
+		errs := map[string]error{"Marshal": marshalErr, "RequestParser": feedErr, "ReadRequest": readErr}
+		for name, err := range errs {
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("%v (%v): expected: %v, actual: %v", test.testName, name, test.expectedErr, err)
+			}
+		}
+		if test.expectedErr != nil || marshalErr != nil || feedErr != nil || readErr != nil {
+			continue
+		}
+		for _, actual := range []*Http11Request{&marshaledReq, p.Request(), readReq} {
+			equals(test.testName+" X", t, test.expected, string(actual.FieldLines.Get("X")))
+		}
+	}
+}
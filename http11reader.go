@@ -0,0 +1,446 @@
+package http11p
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// ReadRequest reads a request from r up to the end of its header section.
+// The message-body is not buffered into MessageBody but exposed as Body,
+// which must be read to EOF or closed before reading the next request from
+// r.
+func ReadRequest(r *bufio.Reader) (req *Http11Request, err error) {
+	return ReadRequestWithOptions(r, DefaultParserOptions())
+}
+
+// ReadRequestWithOptions is like ReadRequest, but reads with options
+// instead of DefaultParserOptions.
+func ReadRequestWithOptions(r *bufio.Reader, options ParserOptions) (req *Http11Request, err error) {
+	req = &Http11Request{}
+	pos := startPosition
+
+	line, err := readStartLine(r, &pos, options, options.checkRequestLine)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := marshalRequestLine(line, req)
+	if err != nil {
+		return nil, locateErrorAt(err, pos, line)
+	}
+	rest := remaining
+	crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
+	if len(crlf) == 0 || len(remaining) != 0 {
+		err = newParseError(ErrInvalidRequestLine, "CRLF", "CRLF after request-line not found", rest)
+		return nil, locateErrorAt(err, pos, line)
+	}
+	pos = pos.advance(line)
+
+	var emptyLine []byte
+	var starts []fieldLineStart
+	req.FieldLines, emptyLine, starts, err = readFieldLines(r, &pos, options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkTransferEncodingVersion(req.HttpVersion, req.FieldLines)
+	if err != nil {
+		return nil, locateFieldLineError(err, starts)
+	}
+	framing, length, err := getBodyFraming(req.FieldLines, true)
+	if err != nil {
+		return nil, locateFieldLineError(err, starts)
+	}
+	req.Body, err = newBodyReader(r, pos.advance(emptyLine), framing, length, options, &req.ChunkExtensions, &req.TrailerSection)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// ReadResponse reads a response from r up to the end of its header
+// section. The message-body is not buffered into MessageBody but exposed as
+// Body, which must be read to EOF or closed before reading the next
+// response from r. If the response is CloseDelimited, Body reads r to EOF.
+func ReadResponse(r *bufio.Reader) (resp *Http11Response, err error) {
+	return ReadResponseForRequest(r, nil)
+}
+
+// ReadResponseForRequest is like ReadResponse, but also applies the message
+// body length rules which depend on method, the method of the request the
+// response answers. After a 2xx response to CONNECT, r is positioned at the
+// data which belongs to the tunnel.
+func ReadResponseForRequest(r *bufio.Reader, method []byte) (resp *Http11Response, err error) {
+	return ReadResponseWithOptions(r, method, DefaultParserOptions())
+}
+
+// ReadResponseWithOptions is like ReadResponseForRequest, but reads with
+// options instead of DefaultParserOptions. method can be nil if the request
+// method is unknown.
+func ReadResponseWithOptions(r *bufio.Reader, method []byte, options ParserOptions) (
+	resp *Http11Response,
+	err error,
+) {
+	resp = &Http11Response{}
+	pos := startPosition
+
+	line, err := readStartLine(r, &pos, options, options.checkStartLine)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := marshalStatusLine(line, resp)
+	if err != nil {
+		return nil, locateErrorAt(err, pos, line)
+	}
+	rest := remaining
+	crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
+	if len(crlf) == 0 || len(remaining) != 0 {
+		err = newParseError(ErrInvalidStatusLine, "CRLF", "CRLF after status-line not found", rest)
+		return nil, locateErrorAt(err, pos, line)
+	}
+	pos = pos.advance(line)
+
+	var emptyLine []byte
+	var starts []fieldLineStart
+	resp.FieldLines, emptyLine, starts, err = readFieldLines(r, &pos, options)
+	if err != nil {
+		return nil, err
+	}
+
+	framing, length, err := getResponseBodyFraming(method, resp.HttpVersion, resp.StatusCode, resp.FieldLines)
+	if err != nil {
+		return nil, locateFieldLineError(err, starts)
+	}
+	resp.CloseDelimited = framing == framingUntilClose
+	resp.Body, err = newBodyReader(r, pos.advance(emptyLine), framing, length, options, &resp.ChunkExtensions, &resp.TrailerSection)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// readLine reads a line including its line terminator. It returns io.EOF
+// only if r is at EOF before the line starts. check is called with the
+// length of the line read so far, so that a line which is too long is
+// rejected before it is complete. pos is the position of the line in the
+// message.
+func readLine(r *bufio.Reader, pos position, check func(n int, data []byte) error) (line []byte, err error) {
+	for {
+		var fragment []byte
+		fragment, err = r.ReadSlice('\n')
+		line = append(line, fragment...)
+		checkErr := check(len(line), line)
+		if checkErr != nil {
+			return nil, locateErrorAt(checkErr, pos, line)
+		}
+		if err != bufio.ErrBufferFull {
+			break
+		}
+	}
+	if err == io.EOF && len(line) > 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// readStartLine reads the start-line, whose length is checked with check.
+// If Lenient, it skips the empty lines before the start-line and advances
+// pos by them.
+func readStartLine(r *bufio.Reader, pos *position, options ParserOptions, check func(n int, data []byte) error) (line []byte, err error) {
+	for {
+		line, err = readLine(r, *pos, check)
+		if err != nil || len(options.skipEmptyLines(line)) != 0 {
+			return line, err
+		}
+		*pos = pos.advance(line)
+	}
+}
+
+// readFieldLines reads *( field-line CRLF ) CRLF. pos is the position of r
+// in the message, which is advanced by the lines read, except the last
+// empty line, which is returned as emptyLine. starts is where each field
+// line starts.
+func readFieldLines(r *bufio.Reader, pos *position, options ParserOptions) (
+	fieldLines []FieldLine,
+	emptyLine []byte,
+	starts []fieldLineStart,
+	err error,
+) {
+	fieldLines = []FieldLine{}
+	limiter := fieldSectionLimiter{options: options}
+	checkFieldLine := func(n int, data []byte) error {
+		if isPartOfEmptyLine(data, options) {
+			return nil
+		}
+		return limiter.check(n, data)
+	}
+	for {
+		line, err := readLine(r, *pos, checkFieldLine)
+		if err == io.EOF {
+			return nil, nil, nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		crlf, _ := abnfp.Parse(line, options.newEolFinder())
+		if len(crlf) == len(line) {
+			return fieldLines, line, starts, nil
+		}
+		if len(fieldLines) > 0 && isContinuationLine(line) {
+			err = limiter.extend(len(line), line)
+			if err == nil {
+				err = unfoldFieldLine(&fieldLines[len(fieldLines)-1], line, options)
+			}
+			if err != nil {
+				return nil, nil, nil, locateErrorAt(err, *pos, line)
+			}
+			*pos = pos.advance(line)
+			continue
+		}
+		err = limiter.add(len(line), line)
+		if err != nil {
+			return nil, nil, nil, locateErrorAt(err, *pos, line)
+		}
+		fieldLine, err := marshalFieldLine(line, options)
+		if err != nil {
+			return nil, nil, nil, locateErrorAt(err, *pos, line)
+		}
+		starts = append(starts, fieldLineStart{pos: *pos, rest: line})
+		*pos = pos.advance(line)
+		fieldLines = append(fieldLines, fieldLine)
+	}
+}
+
+// newBodyReader returns the reader of the message-body, which starts at
+// bodyStart.
+func newBodyReader(
+	r *bufio.Reader,
+	bodyStart position,
+	framing bodyFraming,
+	length int64,
+	options ParserOptions,
+	chunkExtensions *[]ChunkExtension,
+	trailerSection *FieldSection,
+) (body io.ReadCloser, err error) {
+	switch framing {
+	case framingContentLength:
+		err = options.checkBodySize(length, []byte{})
+		if err != nil {
+			return nil, locateErrorAt(err, bodyStart, []byte{})
+		}
+		return &bodyReader{r: &contentLengthReader{r: r, remaining: length}}, nil
+	case framingChunked:
+		return &bodyReader{
+			r: &chunkedReader{
+				r:               r,
+				pos:             bodyStart,
+				options:         options,
+				chunkExtensions: chunkExtensions,
+				trailerSection:  trailerSection,
+			},
+			options:   options,
+			bodyStart: bodyStart,
+		}, nil
+	case framingUntilClose:
+		return &bodyReader{r: r, options: options, bodyStart: bodyStart}, nil
+	}
+	return &bodyReader{r: bytes.NewReader(nil)}, nil
+}
+
+// bodyReader discards the unread part of the message-body on Close, so that
+// the underlying reader is positioned at the next message.
+type bodyReader struct {
+	r      io.Reader
+	closed bool
+
+	// options limits the size of the message-body whose length is not known
+	// in advance.
+	options   ParserOptions
+	bodyStart position
+	size      int64
+}
+
+func (body *bodyReader) Read(p []byte) (n int, err error) {
+	if body.closed {
+		return 0, errors.New("read on closed body")
+	}
+	maxBodySize := body.options.MaxBodySize
+	if maxBodySize > 0 {
+		if body.size > maxBodySize {
+			return 0, body.bodyTooLarge()
+		}
+		// Read at most one byte beyond the limit, which is not returned,
+		// to find that the message-body is too large.
+		if int64(len(p)) > maxBodySize-body.size+1 {
+			p = p[:maxBodySize-body.size+1]
+		}
+	}
+	n, err = body.r.Read(p)
+	body.size += int64(n)
+	if maxBodySize > 0 && body.size > maxBodySize {
+		return n - int(body.size-maxBodySize), body.bodyTooLarge()
+	}
+	return n, err
+}
+
+func (body *bodyReader) bodyTooLarge() error {
+	err := body.options.checkBodySize(body.size, []byte{})
+	return locateErrorAt(err, body.bodyStart, []byte{})
+}
+
+func (body *bodyReader) Close() error {
+	if body.closed {
+		return nil
+	}
+	body.closed = true
+	_, err := io.Copy(io.Discard, body.r)
+	return err
+}
+
+// RFC9112 - 6.3. Message Body Length
+//
+// If the sender closes the connection or the recipient times out before
+// the indicated number of octets are received, the recipient MUST consider
+// the message to be incomplete and close the connection.
+//
+
+type contentLengthReader struct {
+	r         *bufio.Reader
+	remaining int64
+}
+
+func (cl *contentLengthReader) Read(p []byte) (n int, err error) {
+	if cl.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > cl.remaining {
+		p = p[:cl.remaining]
+	}
+	n, err = cl.r.Read(p)
+	cl.remaining -= int64(n)
+	if err == io.EOF && cl.remaining > 0 {
+		return n, io.ErrUnexpectedEOF
+	}
+	if err == nil && cl.remaining == 0 {
+		err = io.EOF
+	}
+	return
+}
+
+// RFC9112 - 7.1. Chunked Transfer Coding
+//
+//  chunked-body   = *chunk
+//                   last-chunk
+//                   trailer-section
+//                   CRLF
+//
+
+// chunkedReader decodes chunked-body. chunk-ext and trailer-section are
+// stored to the message when they are read. Once Read fails, it returns the
+// same error, as the position in chunked-body is lost.
+type chunkedReader struct {
+	r               *bufio.Reader
+	pos             position
+	options         ParserOptions
+	chunkRemaining  int64
+	done            bool
+	err             error
+	chunkExtensions *[]ChunkExtension
+	trailerSection  *FieldSection
+}
+
+func (cr *chunkedReader) Read(p []byte) (n int, err error) {
+	if cr.err != nil {
+		return 0, cr.err
+	}
+	n, err = cr.read(p)
+	if err != nil && err != io.EOF {
+		cr.err = err
+	}
+	return n, err
+}
+
+func (cr *chunkedReader) read(p []byte) (n int, err error) {
+	for cr.chunkRemaining == 0 {
+		if cr.done {
+			return 0, io.EOF
+		}
+		err = cr.readChunkSize()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if int64(len(p)) > cr.chunkRemaining {
+		p = p[:cr.chunkRemaining]
+	}
+	n, err = cr.r.Read(p)
+	cr.chunkRemaining -= int64(n)
+	cr.pos = cr.pos.advance(p[:n])
+	if err == io.EOF {
+		return n, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return n, err
+	}
+
+	if cr.chunkRemaining == 0 {
+		// CRLF after chunk-data
+		crlf := make([]byte, 2)
+		if cr.options.Lenient {
+			next, peekErr := cr.r.Peek(1)
+			if peekErr == nil && next[0] == '\n' {
+				crlf = crlf[:1]
+			}
+		}
+		_, err = io.ReadFull(cr.r, crlf)
+		if err == io.EOF {
+			return n, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return n, err
+		}
+		eol, _ := abnfp.Parse(crlf, cr.options.newEolFinder())
+		if len(eol) != len(crlf) {
+			err = newParseError(ErrInvalidChunkedBody, "CRLF", "CRLF after chunk-data not found", crlf)
+			return n, locateErrorAt(err, cr.pos, crlf)
+		}
+		cr.pos = cr.pos.advance(crlf)
+	}
+	return n, nil
+}
+
+func (cr *chunkedReader) readChunkSize() error {
+	line, err := readLine(cr.r, cr.pos, cr.options.checkChunkSizeLine)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	size, chunkExtensions, err := marshalChunkSizeLine(line, cr.options)
+	if err != nil {
+		return locateErrorAt(err, cr.pos, line)
+	}
+	cr.pos = cr.pos.advance(line)
+	*cr.chunkExtensions = append(*cr.chunkExtensions, chunkExtensions...)
+	if size > 0 {
+		cr.chunkRemaining = size
+		return nil
+	}
+
+	// last-chunk
+	trailerSection, _, _, err := readFieldLines(cr.r, &cr.pos, cr.options)
+	if err != nil {
+		return err
+	}
+	*cr.trailerSection = trailerSection
+	cr.done = true
+	return nil
+}
@@ -0,0 +1,326 @@
+package http11p
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestReadRequest(t *testing.T) {
+	type TestCaseReadRequest struct {
+		testName                string
+		data                    []byte
+		err                     bool
+		expectedRequestTarget   []byte
+		expectedBody            []byte
+		expectedTrailerSection  []FieldLine
+		expectedNextRequestLine []byte
+	}
+
+	tests := []TestCaseReadRequest{
+		{
+			testName: "without body",
+			data: []byte(
+				"GET /1 HTTP/1.1\r\n" +
+					"Host: example.com\r\n" +
+					"\r\n" +
+					"GET /2 HTTP/1.1\r\n" +
+					"\r\n",
+			),
+			err:                     false,
+			expectedRequestTarget:   []byte("/1"),
+			expectedBody:            []byte{},
+			expectedNextRequestLine: []byte("GET /2 HTTP/1.1\r\n"),
+		},
+		{
+			testName: "Content-Length",
+			data: []byte(
+				"POST /1 HTTP/1.1\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg" +
+					"GET /2 HTTP/1.1\r\n" +
+					"\r\n",
+			),
+			err:                     false,
+			expectedRequestTarget:   []byte("/1"),
+			expectedBody:            []byte("abcdefg"),
+			expectedNextRequestLine: []byte("GET /2 HTTP/1.1\r\n"),
+		},
+		{
+			testName: "chunked",
+			data: []byte(
+				"POST /1 HTTP/1.1\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"\r\n" +
+					"3\r\nabc\r\n" +
+					"4;foo=bar\r\ndefg\r\n" +
+					"0\r\n" +
+					"Expires: 0\r\n" +
+					"\r\n" +
+					"GET /2 HTTP/1.1\r\n" +
+					"\r\n",
+			),
+			err:                   false,
+			expectedRequestTarget: []byte("/1"),
+			expectedBody:          []byte("abcdefg"),
+			expectedTrailerSection: []FieldLine{
+				{FieldName: []byte("Expires"), FieldValue: []byte("0")},
+			},
+			expectedNextRequestLine: []byte("GET /2 HTTP/1.1\r\n"),
+		},
+		{
+			testName: "absolute-form with IPv6 address",
+			data: []byte(
+				"GET http://[2001:db8::1]:8080/x HTTP/1.1\r\n" +
+					"Host: [2001:db8::1]:8080\r\n" +
+					"\r\n" +
+					"GET /2 HTTP/1.1\r\n" +
+					"\r\n",
+			),
+			err:                     false,
+			expectedRequestTarget:   []byte("http://[2001:db8::1]:8080/x"),
+			expectedBody:            []byte{},
+			expectedNextRequestLine: []byte("GET /2 HTTP/1.1\r\n"),
+		},
+		{
+			testName: "invalid request-line",
+			data:     []byte("GET / HTTP/1.1 \r\n\r\n"),
+			err:      true,
+		},
+		{
+			testName: "incomplete header section",
+			data:     []byte("GET / HTTP/1.1\r\nHost: example.com\r\n"),
+			err:      true,
+		},
+		{
+			testName: "request-target is not absolute-URI",
+			data:     []byte("GET ab HTTP/1.1\r\n\r\n"),
+			err:      true,
+		},
+		{
+			testName: "SP in method",
+			data:     []byte("P ST / HTTP/1.1\r\n\r\n"),
+			err:      true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			r := bufio.NewReader(bytes.NewReader(testCase.data))
+			req, err := ReadRequest(r)
+			if err != nil {
+				if !testCase.err {
+					t.Errorf("Failed to read Http/1.1 Request: %v", err.Error())
+				}
+				return
+			}
+			if testCase.err {
+				t.Errorf("Unexpectedly read Http/1.1 Request successfully: %v", req)
+				return
+			}
+			if !byteEquals(testCase.expectedRequestTarget, req.RequestTarget) {
+				t.Errorf("expectedRequestTarget: %s, actual: %s",
+					testCase.expectedRequestTarget, req.RequestTarget)
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Errorf("Failed to read Body: %v", err.Error())
+				return
+			}
+			if !byteEquals(testCase.expectedBody, body) {
+				t.Errorf("expectedBody: %s, actual: %s", testCase.expectedBody, body)
+			}
+			if len(testCase.expectedTrailerSection) != len(req.TrailerSection) {
+				t.Errorf("expectedTrailerSection: %s, actual: %s",
+					testCase.expectedTrailerSection, req.TrailerSection)
+			}
+			line, _ := r.ReadBytes('\n')
+			if !byteEquals(testCase.expectedNextRequestLine, line) {
+				t.Errorf("expectedNextRequestLine: %s, actual: %s",
+					testCase.expectedNextRequestLine, line)
+			}
+		})
+	}
+}
+
+func TestReadRequestBodyClose(t *testing.T) {
+	r := bufio.NewReader(bytes.NewReader([]byte(
+		"POST /1 HTTP/1.1\r\n" +
+			"Content-Length: 7\r\n" +
+			"\r\n" +
+			"abcdefg" +
+			"GET /2 HTTP/1.1\r\n" +
+			"\r\n",
+	)))
+	req, err := ReadRequest(r)
+	if err != nil {
+		t.Errorf("Failed to read Http/1.1 Request: %v", err.Error())
+		return
+	}
+	err = req.Body.Close()
+	if err != nil {
+		t.Errorf("Failed to close Body: %v", err.Error())
+		return
+	}
+
+	req, err = ReadRequest(r)
+	if err != nil {
+		t.Errorf("Failed to read Http/1.1 Request: %v", err.Error())
+		return
+	}
+	if string(req.RequestTarget) != "/2" {
+		t.Errorf("expected: /2, actual: %s", req.RequestTarget)
+	}
+
+	_, err = ReadRequest(r)
+	if err != io.EOF {
+		t.Errorf("expected: %v, actual: %v", io.EOF, err)
+	}
+}
+
+// Once Body fails, it returns the same error without reading the chunks
+// which follow the invalid data.
+func TestReadRequestBodyErrorIsSticky(t *testing.T) {
+	r := bufio.NewReader(bytes.NewReader([]byte(
+		"POST / HTTP/1.1\r\n" +
+			"Transfer-Encoding: chunked\r\n" +
+			"\r\n" +
+			"3\r\nabcXY" +
+			"3\r\ndef\r\n0\r\n\r\n",
+	)))
+	req, err := ReadRequest(r)
+	if err != nil {
+		t.Errorf("Failed to read Http/1.1 Request: %v", err.Error())
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if !errors.Is(err, ErrInvalidChunkedBody) {
+		t.Errorf("expected: %v, actual: %v", ErrInvalidChunkedBody, err)
+	}
+	equals("Body", t, "abc", string(body))
+
+	n, secondErr := req.Body.Read(make([]byte, 8))
+	equals("Read after the error", t, 0, n)
+	if secondErr != err {
+		t.Errorf("expected: %v, actual: %v", err, secondErr)
+	}
+}
+
+func TestReadResponse(t *testing.T) {
+	type TestCaseReadResponse struct {
+		testName               string
+		data                   []byte
+		err                    bool
+		bodyErr                bool
+		expectedBody           []byte
+		expectedCloseDelimited bool
+	}
+
+	tests := []TestCaseReadResponse{
+		{
+			testName: "Content-Length",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg",
+			),
+			expectedBody: []byte("abcdefg"),
+		},
+		{
+			testName: "delimited by the closing of the connection",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"\r\n" +
+					"abcdefg",
+			),
+			expectedBody:           []byte("abcdefg"),
+			expectedCloseDelimited: true,
+		},
+		{
+			testName: "body shorter than Content-Length",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abc",
+			),
+			bodyErr: true,
+		},
+		{
+			testName: "incomplete chunked-body",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"\r\n" +
+					"7\r\n" +
+					"abc",
+			),
+			bodyErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			resp, err := ReadResponse(bufio.NewReader(bytes.NewReader(testCase.data)))
+			if err != nil {
+				if !testCase.err {
+					t.Errorf("Failed to read Http/1.1 Response: %v", err.Error())
+				}
+				return
+			}
+			if testCase.err {
+				t.Errorf("Unexpectedly read Http/1.1 Response successfully: %v", resp)
+				return
+			}
+			equals(testCase.testName, t, testCase.expectedCloseDelimited, resp.CloseDelimited)
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				if !testCase.bodyErr {
+					t.Errorf("Failed to read Body: %v", err.Error())
+				}
+				return
+			}
+			if testCase.bodyErr {
+				t.Errorf("Unexpectedly read Body successfully: %s", body)
+				return
+			}
+			if !byteEquals(testCase.expectedBody, body) {
+				t.Errorf("expectedBody: %s, actual: %s", testCase.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestReadResponseForRequest(t *testing.T) {
+	r := bufio.NewReader(bytes.NewReader([]byte(
+		"HTTP/1.1 200 OK\r\n" +
+			"Content-Length: 7\r\n" +
+			"\r\n" +
+			"HTTP/1.1 204 No Content\r\n" +
+			"\r\n",
+	)))
+
+	resp, err := ReadResponseForRequest(r, []byte("HEAD"))
+	if err != nil {
+		t.Errorf("Failed to read Http/1.1 Response: %v", err.Error())
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || len(body) != 0 {
+		t.Errorf("expected empty Body, actual: %s, %v", body, err)
+		return
+	}
+
+	resp, err = ReadResponseForRequest(r, []byte("GET"))
+	if err != nil {
+		t.Errorf("Failed to read Http/1.1 Response: %v", err.Error())
+		return
+	}
+	if string(resp.StatusCode) != "204" {
+		t.Errorf("expected: 204, actual: %s", resp.StatusCode)
+	}
+}
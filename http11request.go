@@ -1,7 +1,8 @@
 package http11p
 
 import (
-	"errors"
+	"bytes"
+	"io"
 
 	abnfp "github.com/um7a/abnf-parser"
 )
@@ -10,8 +11,19 @@ type Http11Request struct {
 	Method        []byte
 	RequestTarget []byte
 	HttpVersion   []byte
-	FieldLines    []FieldLine
+	FieldLines    FieldSection
 	MessageBody   []byte
+
+	// Set when the message-body is sent with the chunked transfer coding.
+	// ChunkExtensions are the chunk-ext of all the chunks including
+	// last-chunk in order. Unmarshal sends MessageBody as a single chunk
+	// with all ChunkExtensions, so the chunk boundaries and the chunk each
+	// extension belonged to are not preserved.
+	ChunkExtensions []ChunkExtension
+	TrailerSection  FieldSection
+
+	// Set by ReadRequest instead of MessageBody.
+	Body io.ReadCloser
 }
 
 func marshalRequestLine(data []byte, req *Http11Request) (remaining []byte, err error) {
@@ -19,62 +31,163 @@ func marshalRequestLine(data []byte, req *Http11Request) (remaining []byte, err
 
 	req.Method, remaining = abnfp.Parse(remaining, NewMethodFinder())
 	if len(req.Method) == 0 {
-		return data, errors.New("method not found")
+		return data, newParseError(ErrInvalidRequestLine, "method", "method not found", remaining)
 	}
 
+	rest := remaining
 	sp, remaining := abnfp.Parse(remaining, abnfp.NewSpFinder())
 	if len(sp) == 0 {
-		return data, errors.New("SP after method not found")
+		return data, newParseError(ErrInvalidRequestLine, "SP", "SP after method not found", rest)
 	}
 
-	req.RequestTarget, remaining = abnfp.Parse(remaining, NewRequestTargetFinder())
-	if len(req.RequestTarget) == 0 {
-		return data, errors.New("request-target not found")
+	// The request-target ends at the next SP. Parse it as a whole, so that
+	// no form can match a prefix of a malformed request-target.
+	end := bytes.IndexByte(remaining, ' ')
+	if end < 0 || !matchesAll(remaining[:end], NewRequestTargetFinder()) {
+		return data, newParseError(ErrInvalidRequestLine, "request-target", "request-target not found", remaining)
 	}
+	req.RequestTarget, remaining = remaining[:end], remaining[end:]
 
+	rest = remaining
 	sp, remaining = abnfp.Parse(remaining, abnfp.NewSpFinder())
 	if len(sp) == 0 {
-		return data, errors.New("SP after request-target not found")
+		return data, newParseError(ErrInvalidRequestLine, "SP", "SP after request-target not found", rest)
 	}
 
+	rest = remaining
 	req.HttpVersion, remaining = abnfp.Parse(remaining, NewHttpVersionFinder())
 	if len(req.HttpVersion) == 0 {
-		return data, errors.New("http-version not found")
+		return data, newParseError(ErrInvalidRequestLine, "HTTP-version", "http-version not found", remaining)
+	}
+	err = checkHttpVersion(req.HttpVersion, rest)
+	if err != nil {
+		return data, err
 	}
 
 	return
 }
 
+// isRequestLinePrefix reports whether data is the part of request-line
+// received so far.
+func isRequestLinePrefix(data []byte) bool {
+	method, remaining := abnfp.Parse(data, NewMethodFinder())
+	if len(remaining) == 0 {
+		return true
+	}
+	if len(method) == 0 || remaining[0] != ' ' {
+		return false
+	}
+	remaining = remaining[1:]
+	end := bytes.IndexByte(remaining, ' ')
+	if end < 0 {
+		return isRequestTargetPrefix(remaining)
+	}
+	return matchesAll(remaining[:end], NewRequestTargetFinder()) && isHttpVersionPrefix(remaining[end+1:])
+}
+
+// Marshal parses one request from data. It applies the limits of
+// DefaultParserOptions, e.g. a field line longer than 8 KiB is rejected with
+// ErrFieldLineTooLong; use MarshalWithOptions to change or remove them.
 func (req *Http11Request) Marshal(data []byte) (err error) {
-	remaining := data
+	_, err = req.MarshalWithRemaining(data)
+	return err
+}
+
+// MarshalWithRemaining parses one request from data and returns the bytes
+// following its message-body, e.g. the next request on the connection.
+//
+// If the request cannot be parsed, the error is a *ParseError located in
+// data.
+func (req *Http11Request) MarshalWithRemaining(data []byte) (remaining []byte, err error) {
+	return req.MarshalWithOptions(data, DefaultParserOptions())
+}
+
+// MarshalWithOptions is like MarshalWithRemaining, but parses data with
+// options instead of DefaultParserOptions.
+func (req *Http11Request) MarshalWithOptions(data []byte, options ParserOptions) (remaining []byte, err error) {
+	remaining = options.skipEmptyLines(data)
 
+	err = options.checkRequestLine(lineLength(remaining), remaining)
+	if err != nil {
+		return data, locateError(err, data)
+	}
+
+	requestLine := remaining
 	remaining, err = marshalRequestLine(remaining, req)
 	if err != nil {
-		return err
+		err = orIncomplete(err, "request-line", requestLine, NewRequestLineFinder(), isRequestLinePrefix)
+		return data, locateError(err, data)
 	}
 
-	crlf, remaining := abnfp.Parse(remaining, abnfp.NewCrLfFinder())
+	rest := remaining
+	crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
 	if len(crlf) == 0 {
-		return errors.New("CRLF after request-line not found")
+		err = newParseError(ErrInvalidRequestLine, "CRLF", "CRLF after request-line not found", rest)
+		err = orIncomplete(err, "request-line", requestLine, NewRequestLineFinder(), isRequestLinePrefix)
+		return data, locateError(err, data)
 	}
 
-	req.FieldLines, remaining, err = marshalFieldLines(remaining)
+	fieldSection := remaining
+	req.FieldLines, remaining, err = marshalFieldLines(remaining, options)
 	if err != nil {
-		return err
+		return data, locateError(err, data)
 	}
 
-	crlf, remaining = abnfp.Parse(remaining, abnfp.NewCrLfFinder())
+	rest = remaining
+	crlf, remaining = abnfp.Parse(remaining, options.newEolFinder())
 	if len(crlf) == 0 {
-		return errors.New("CRLF before message-body not found")
+		err = newParseError(ErrIncompleteMessage, "CRLF", "message ends before the end of the header section", rest)
+		return data, locateError(err, data)
+	}
+
+	err = checkTransferEncodingVersion(req.HttpVersion, req.FieldLines)
+	if err != nil {
+		pos := startPosition.advance(data[:len(data)-len(fieldSection)])
+		return data, locateFieldLineError(err, scanFieldLineStarts(fieldSection, pos, options))
 	}
-	req.MessageBody = remaining
-	return nil
+	framing, length, err := getBodyFraming(req.FieldLines, true)
+	if err != nil {
+		pos := startPosition.advance(data[:len(data)-len(fieldSection)])
+		return data, locateFieldLineError(err, scanFieldLineStarts(fieldSection, pos, options))
+	}
+	req.MessageBody, req.ChunkExtensions, req.TrailerSection, remaining, err =
+		marshalMessageBody(remaining, framing, length, options)
+	if err != nil {
+		return data, locateError(err, data)
+	}
+	return remaining, nil
+}
+
+// MarshalRequests parses pipelined requests from data until data is
+// consumed. If a request cannot be parsed, it returns the requests parsed so
+// far, the data starting from the failed request and the error.
+func MarshalRequests(data []byte) (reqs []Http11Request, remaining []byte, err error) {
+	return MarshalRequestsWithOptions(data, DefaultParserOptions())
+}
+
+// MarshalRequestsWithOptions is like MarshalRequests, but parses data with
+// options instead of DefaultParserOptions.
+func MarshalRequestsWithOptions(data []byte, options ParserOptions) (
+	reqs []Http11Request,
+	remaining []byte,
+	err error,
+) {
+	reqs = []Http11Request{}
+	remaining = data
+	for len(remaining) > 0 {
+		var req Http11Request
+		remaining, err = req.MarshalWithOptions(remaining, options)
+		if err != nil {
+			return reqs, remaining, locateError(err, data)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, remaining, nil
 }
 
 func (req Http11Request) Unmarshal() (data []byte) {
 	sp := []byte(" ")
 	crlf := []byte("\r\n")
-	colon := []byte(":")
 
 	data = append(data, req.Method...)
 	data = append(data, sp...)
@@ -82,15 +195,13 @@ func (req Http11Request) Unmarshal() (data []byte) {
 	data = append(data, sp...)
 	data = append(data, req.HttpVersion...)
 	data = append(data, crlf...)
-	for _, fieldLine := range req.FieldLines {
-		data = append(data, fieldLine.FieldName...)
-		data = append(data, colon...)
-		data = append(data, sp...)
-		data = append(data, fieldLine.FieldValue...)
-		data = append(data, crlf...)
-	}
+	data = append(data, unmarshalFieldLines(req.FieldLines)...)
 	data = append(data, crlf...)
 
+	if isChunked(req.FieldLines) {
+		data = append(data, unmarshalChunkedBody(req.MessageBody, req.ChunkExtensions, req.TrailerSection)...)
+		return
+	}
 	data = append(data, req.MessageBody...)
 	return
 }
@@ -101,10 +212,5 @@ func (req Http11Request) String() string {
 }
 
 func (req Http11Request) GetHeader(name string) []byte {
-	for _, fieldLine := range req.FieldLines {
-		if string(fieldLine.FieldName) == name {
-			return fieldLine.FieldValue
-		}
-	}
-	return nil
+	return req.FieldLines.Get(name)
 }
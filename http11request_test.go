@@ -13,6 +13,20 @@ type TestCaseForHttp11RequestMarshal struct {
 	expectedMessageBody   []byte
 }
 
+type TestCaseForHttp11RequestMarshalWithRemaining struct {
+	testName            string
+	data                []byte
+	err                 bool
+	expectedMessageBody []byte
+	expectedRemaining   []byte
+}
+
+type TestCaseForHttp11RequestRoundTrip struct {
+	testName            string
+	data                []byte
+	expectedMessageBody []byte
+}
+
 type TestCaseForHttp11RequestUnmarshal struct {
 	testName      string
 	req           Http11Request
@@ -101,6 +115,81 @@ func execTestForHttp11RequestMarshal(tests []TestCaseForHttp11RequestMarshal, t
 					return
 				}
 			}
+
+			equals = byteEquals(testCase.expectedMessageBody, req.MessageBody)
+			if !equals {
+				t.Errorf("expectedMessageBody: %v, actual: %v",
+					testCase.expectedMessageBody, req.MessageBody)
+				return
+			}
+		})
+	}
+}
+
+func execTestForHttp11RequestMarshalWithRemaining(tests []TestCaseForHttp11RequestMarshalWithRemaining, t *testing.T) {
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			var req Http11Request
+			remaining, err := req.MarshalWithRemaining(testCase.data)
+
+			if err != nil && testCase.err == false {
+				t.Errorf("Failed to marshal Http/1.1 Request: %v", err.Error())
+				return
+			}
+			if err == nil && testCase.err == true {
+				t.Errorf("Unexpectedly marshal Http/1.1 Request successfully: %v", req)
+				return
+			}
+			if err != nil && testCase.err == true {
+				// test success.
+				return
+			}
+
+			if !byteEquals(testCase.expectedMessageBody, req.MessageBody) {
+				t.Errorf("expectedMessageBody: %v, actual: %v",
+					testCase.expectedMessageBody, req.MessageBody)
+				return
+			}
+
+			if !byteEquals(testCase.expectedRemaining, remaining) {
+				t.Errorf("expectedRemaining: %v, actual: %v",
+					testCase.expectedRemaining, remaining)
+				return
+			}
+		})
+	}
+}
+
+func execTestForHttp11RequestRoundTrip(tests []TestCaseForHttp11RequestRoundTrip, t *testing.T) {
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			var req Http11Request
+			err := req.Marshal(testCase.data)
+			if err != nil {
+				t.Errorf("Failed to marshal Http/1.1 Request: %v", err.Error())
+				return
+			}
+			if !byteEquals(testCase.expectedMessageBody, req.MessageBody) {
+				t.Errorf("expectedMessageBody: %q, actual: %q",
+					testCase.expectedMessageBody, req.MessageBody)
+				return
+			}
+
+			actualBytes := req.Unmarshal()
+			if !byteEquals(testCase.data, actualBytes) {
+				t.Errorf("expected: %q, actual: %q", testCase.data, actualBytes)
+				return
+			}
+
+			var again Http11Request
+			err = again.Marshal(actualBytes)
+			if err != nil {
+				t.Errorf("Failed to marshal unmarshaled Http/1.1 Request: %v", err.Error())
+				return
+			}
+			if again.String() != req.String() {
+				t.Errorf("expected: %q, actual: %q", req.String(), again.String())
+			}
 		})
 	}
 }
@@ -188,6 +277,169 @@ func TestHttp11RequestMarshal(t *testing.T) {
 	execTestForHttp11RequestMarshal(tests, t)
 }
 
+func TestHttp11RequestMarshalWithRemaining(t *testing.T) {
+	tests := []TestCaseForHttp11RequestMarshalWithRemaining{
+		{
+			testName:            "data: []byte{}",
+			data:                []byte{},
+			err:                 true,
+			expectedMessageBody: []byte{},
+			expectedRemaining:   []byte{},
+		},
+		{
+			testName: "without body, followed by next request",
+			data: []byte(
+				"GET /index.html HTTP/1.1\r\n" +
+					"\r\n" +
+					"GET /favicon.ico HTTP/1.1\r\n" +
+					"\r\n",
+			),
+			err:                 false,
+			expectedMessageBody: []byte{},
+			expectedRemaining: []byte(
+				"GET /favicon.ico HTTP/1.1\r\n" +
+					"\r\n",
+			),
+		},
+		{
+			testName: "with body, followed by next request",
+			data: []byte(
+				"POST / HTTP/1.1\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg" +
+					"GET / HTTP/1.1\r\n" +
+					"\r\n",
+			),
+			err:                 false,
+			expectedMessageBody: []byte("abcdefg"),
+			expectedRemaining: []byte(
+				"GET / HTTP/1.1\r\n" +
+					"\r\n",
+			),
+		},
+		{
+			testName: "chunked body, followed by next request",
+			data: []byte(
+				"POST / HTTP/1.1\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"\r\n" +
+					"3\r\n" +
+					"abc\r\n" +
+					"4\r\n" +
+					"defg\r\n" +
+					"0\r\n" +
+					"\r\n" +
+					"GET / HTTP/1.1\r\n" +
+					"\r\n",
+			),
+			err:                 false,
+			expectedMessageBody: []byte("abcdefg"),
+			expectedRemaining: []byte(
+				"GET / HTTP/1.1\r\n" +
+					"\r\n",
+			),
+		},
+		{
+			testName: "body shorter than Content-Length",
+			data: []byte(
+				"POST / HTTP/1.1\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abc",
+			),
+			err: true,
+		},
+		{
+			testName: "conflicting Content-Length",
+			data: []byte(
+				"POST / HTTP/1.1\r\n" +
+					"Content-Length: 7\r\n" +
+					"Content-Length: 3\r\n" +
+					"\r\n" +
+					"abcdefg",
+			),
+			err: true,
+		},
+	}
+	execTestForHttp11RequestMarshalWithRemaining(tests, t)
+}
+
+func TestHttp11RequestRoundTrip(t *testing.T) {
+	tests := []TestCaseForHttp11RequestRoundTrip{
+		{
+			testName: "GET without body",
+			data: []byte(
+				"GET /pub/WWW/TheProject.html HTTP/1.1\r\n" +
+					"Host: www.example.org\r\n" +
+					"User-Agent: curl/7.64.1\r\n" +
+					"Accept: */*\r\n" +
+					"\r\n",
+			),
+			expectedMessageBody: []byte{},
+		},
+		{
+			testName: "POST with form body",
+			data: []byte(
+				"POST /login?next=%2Fhome HTTP/1.1\r\n" +
+					"Host: www.example.com\r\n" +
+					"Content-Type: application/x-www-form-urlencoded\r\n" +
+					"Content-Length: 29\r\n" +
+					"\r\n" +
+					"user=alice&password=s3cr3t%21",
+			),
+			expectedMessageBody: []byte("user=alice&password=s3cr3t%21"),
+		},
+		{
+			testName: "PUT with binary body",
+			data: []byte(
+				"PUT /upload/image.png HTTP/1.1\r\n" +
+					"Host: www.example.com\r\n" +
+					"Content-Type: image/png\r\n" +
+					"Content-Length: 8\r\n" +
+					"\r\n" +
+					"\x89PNG\r\n\x1a\n",
+			),
+			expectedMessageBody: []byte("\x89PNG\r\n\x1a\n"),
+		},
+		{
+			testName: "POST with chunked body and trailer-section",
+			data: []byte(
+				"POST /stream HTTP/1.1\r\n" +
+					"Host: www.example.com\r\n" +
+					"Transfer-Encoding: gzip, chunked\r\n" +
+					"Trailer: Content-MD5\r\n" +
+					"\r\n" +
+					"a;ext=\"quoted value\"\r\n" +
+					"0123456789\r\n" +
+					"0\r\n" +
+					"Content-MD5: Q2hlY2sgSW50ZWdyaXR5IQ==\r\n" +
+					"\r\n",
+			),
+			expectedMessageBody: []byte("0123456789"),
+		},
+		{
+			testName: "OPTIONS with asterisk-form",
+			data: []byte(
+				"OPTIONS * HTTP/1.1\r\n" +
+					"Host: www.example.com\r\n" +
+					"\r\n",
+			),
+			expectedMessageBody: []byte{},
+		},
+		{
+			testName: "CONNECT with authority-form",
+			data: []byte(
+				"CONNECT www.example.com:443 HTTP/1.1\r\n" +
+					"Host: www.example.com:443\r\n" +
+					"\r\n",
+			),
+			expectedMessageBody: []byte{},
+		},
+	}
+	execTestForHttp11RequestRoundTrip(tests, t)
+}
+
 func TestHttp11RequestUnMarshal(t *testing.T) {
 	tests := []TestCaseForHttp11RequestUnmarshal{
 		{
@@ -226,6 +478,35 @@ func TestHttp11RequestUnMarshal(t *testing.T) {
 				"\r\n" +
 				"abcdefg"),
 		},
+		{
+			testName: "with chunked body",
+			req: Http11Request{
+				Method:        []byte("POST"),
+				RequestTarget: []byte("/"),
+				HttpVersion:   []byte("HTTP/1.1"),
+				FieldLines: []FieldLine{
+					{
+						FieldName:  []byte("Transfer-Encoding"),
+						FieldValue: []byte("chunked"),
+					},
+				},
+				MessageBody: []byte("abcdefg"),
+				TrailerSection: []FieldLine{
+					{
+						FieldName:  []byte("Expires"),
+						FieldValue: []byte("0"),
+					},
+				},
+			},
+			expectedBytes: []byte("POST / HTTP/1.1\r\n" +
+				"Transfer-Encoding: chunked\r\n" +
+				"\r\n" +
+				"7\r\n" +
+				"abcdefg\r\n" +
+				"0\r\n" +
+				"Expires: 0\r\n" +
+				"\r\n"),
+		},
 	}
 	execTestForHttp11RequestUnmarshal(tests, t)
 }
@@ -306,6 +587,96 @@ func TestHttp11RequestGetHeader(t *testing.T) {
 			fieldName:          "Content-Length",
 			expectedFieldValue: []byte{},
 		},
+		{
+			testName: "existing field in different case",
+			req: Http11Request{
+				Method:        []byte("GET"),
+				RequestTarget: []byte("/index.html"),
+				HttpVersion:   []byte("HTTP/1.1"),
+				FieldLines: []FieldLine{
+					{
+						FieldName:  []byte("Cache-Control"),
+						FieldValue: []byte("no-cache"),
+					},
+				},
+			},
+			fieldName:          "cache-control",
+			expectedFieldValue: []byte("no-cache"),
+		},
 	}
 	execTestForHttp11RequestGetHeader(tests, t)
 }
+
+func TestMarshalRequests(t *testing.T) {
+	type TestCaseMarshalRequests struct {
+		testName               string
+		data                   []byte
+		err                    bool
+		expectedRequestTargets []string
+		expectedRemaining      []byte
+	}
+
+	tests := []TestCaseMarshalRequests{
+		{
+			testName:               "data: []byte{}",
+			data:                   []byte{},
+			err:                    false,
+			expectedRequestTargets: []string{},
+			expectedRemaining:      []byte{},
+		},
+		{
+			testName: "pipelined requests",
+			data: []byte(
+				"GET /1 HTTP/1.1\r\n" +
+					"\r\n" +
+					"POST /2 HTTP/1.1\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg" +
+					"POST /3 HTTP/1.1\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"\r\n" +
+					"7\r\nabcdefg\r\n0\r\n\r\n",
+			),
+			err:                    false,
+			expectedRequestTargets: []string{"/1", "/2", "/3"},
+			expectedRemaining:      []byte{},
+		},
+		{
+			testName: "pipelined requests followed by incomplete request",
+			data: []byte(
+				"GET /1 HTTP/1.1\r\n" +
+					"\r\n" +
+					"GET /2 HTTP/1.1\r\n",
+			),
+			err:                    true,
+			expectedRequestTargets: []string{"/1"},
+			expectedRemaining:      []byte("GET /2 HTTP/1.1\r\n"),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			reqs, remaining, err := MarshalRequests(testCase.data)
+			if err != nil && !testCase.err {
+				t.Errorf("Failed to marshal Http/1.1 Requests: %v", err.Error())
+				return
+			}
+			if err == nil && testCase.err {
+				t.Errorf("Unexpectedly marshal Http/1.1 Requests successfully: %v", reqs)
+				return
+			}
+			if len(testCase.expectedRequestTargets) != len(reqs) {
+				t.Errorf("len(expectedRequestTargets): %v, len(actual): %v",
+					len(testCase.expectedRequestTargets), len(reqs))
+				return
+			}
+			for i, expected := range testCase.expectedRequestTargets {
+				equals(testCase.testName, t, expected, string(reqs[i].RequestTarget))
+			}
+			if !byteEquals(testCase.expectedRemaining, remaining) {
+				t.Errorf("expectedRemaining: %s, actual: %s", testCase.expectedRemaining, remaining)
+			}
+		})
+	}
+}
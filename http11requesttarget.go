@@ -0,0 +1,268 @@
+package http11p
+
+import (
+	"bytes"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC9112 - 3.2. Request Target
+//
+// There are four distinct formats for the request-target, depending on both
+// the method being requested and whether the request is to a proxy.
+//
+//  request-target = origin-form
+//                 / absolute-form
+//                 / authority-form
+//                 / asterisk-form
+//
+
+type RequestTargetForm int
+
+const (
+	OriginForm RequestTargetForm = iota
+	AbsoluteForm
+	AuthorityForm
+	AsteriskForm
+)
+
+func (form RequestTargetForm) String() string {
+	switch form {
+	case OriginForm:
+		return "origin-form"
+	case AbsoluteForm:
+		return "absolute-form"
+	case AuthorityForm:
+		return "authority-form"
+	case AsteriskForm:
+		return "asterisk-form"
+	}
+	return "unknown"
+}
+
+// RequestTarget is the request-target of a request split into its
+// components. The components which do not appear in the form are nil.
+type RequestTarget struct {
+	Form RequestTargetForm
+
+	// Set for absolute-form.
+	Scheme []byte
+
+	// Set for absolute-form with an authority, and for authority-form.
+	Host []byte
+	Port []byte
+
+	// Path is the path of origin-form and absolute-form, and Segments is
+	// Path split at "/". The empty path of absolute-form has no segments.
+	Path     []byte
+	Segments [][]byte
+
+	// Set for origin-form and absolute-form when the target contains "?".
+	// Query does not include "?", and can be empty.
+	Query []byte
+}
+
+// RFC9112 - 3.2.3. authority-form
+//
+// The "authority-form" of request-target is only used for CONNECT requests
+// (Section 9.3.6 of [HTTP]).
+//
+// RFC9112 - 3.2.4. asterisk-form
+//
+// The "asterisk-form" of request-target is only used for a server-wide
+// OPTIONS request (Section 9.3.7 of [HTTP]).
+//
+
+// ParseRequestTarget detects the form of req.RequestTarget and splits it into
+// its components. The request-target of CONNECT must be in authority-form,
+// and that of the other methods is in one of the other forms, because
+// "host:port" is also a valid absolute-URI.
+//
+// If the request-target is invalid, the error is a *ParseError wrapping
+// ErrInvalidRequestTarget.
+func (req Http11Request) ParseRequestTarget() (target RequestTarget, err error) {
+	data := req.RequestTarget
+	if Method(req.Method) == MethodConnect {
+		if !matchesAll(data, NewAuthorityFormFinder()) {
+			return target, newInvalidRequestTargetError("authority-form not found")
+		}
+		target.Form = AuthorityForm
+		target.Host, target.Port = splitAuthorityForm(data)
+		return target, nil
+	}
+
+	switch {
+	case matchesAll(data, NewOriginFormFinder()):
+		target.Form = OriginForm
+		target.Path, target.Query = splitOriginForm(data)
+	case matchesAll(data, NewAsteriskFormFinder()):
+		target.Form = AsteriskForm
+	default:
+		finder := &absoluteFormFinder{}
+		if !matchesAll(data, finder) {
+			return target, newInvalidRequestTargetError("request-target not found")
+		}
+		target.Form = AbsoluteForm
+		target.Scheme = finder.uri.scheme
+		target.Host = finder.uri.host
+		target.Port = finder.uri.port
+		target.Path = finder.uri.path
+		target.Query = finder.uri.query
+	}
+	target.Segments = splitSegments(target.Path)
+	return target, nil
+}
+
+func newInvalidRequestTargetError(message string) error {
+	return newParseError(ErrInvalidRequestTarget, "request-target", message, nil)
+}
+
+// matchesAll reports whether finder matches the whole data.
+func matchesAll(data []byte, finder abnfp.Finder) bool {
+	found, end := finder.Find(data)
+	return found && end == len(data)
+}
+
+// isRequestTargetPrefix reports whether data is the part of a
+// request-target received so far. It only checks that data consists of the
+// characters of the four forms.
+func isRequestTargetPrefix(data []byte) bool {
+	for _, b := range data {
+		isAlphaNum := ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+		if !isAlphaNum && bytes.IndexByte([]byte("-._~!$&'()*+,;=:@/?%[]"), b) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func splitOriginForm(data []byte) (path []byte, query []byte) {
+	path, remaining := abnfp.Parse(data, NewAbsolutePathFinder())
+	if len(remaining) > 0 {
+		// Skip "?".
+		query = remaining[1:]
+	}
+	return
+}
+
+func splitAuthorityForm(data []byte) (host []byte, port []byte) {
+	host, remaining := abnfp.Parse(data, NewUriHostFinder())
+	// Skip ":".
+	port = remaining[1:]
+	return
+}
+
+// splitSegments splits path-absolute, path-abempty or path-rootless into
+// segments.
+func splitSegments(path []byte) [][]byte {
+	if len(path) == 0 {
+		return nil
+	}
+	if path[0] == '/' {
+		path = path[1:]
+	}
+	return bytes.Split(path, []byte("/"))
+}
+
+// RFC9112 - 3.3. Reconstructing the Target URI
+//
+// If the request-target is in absolute-form, the target URI is the same as
+// the request-target. Otherwise, the target URI is constructed as follows.
+//
+// If the server's configuration provides for a fixed URI scheme, or a scheme
+// is provided by a trusted outbound gateway, that scheme is used for the
+// target URI. Otherwise, if the request is received over a secured
+// connection, the target URI's scheme is "https"; if not, the scheme is
+// "http".
+//
+// If the server's configuration provides for a fixed URI authority
+// component, that authority is used for the target URI. If not, then if the
+// request-target is in authority-form, the target URI's authority component
+// is the request-target. Otherwise, the target URI's authority component is
+// the field value of the Host header field. If there is no Host header field
+// or if its field value is empty or invalid, the target URI's authority
+// component is empty.
+//
+// If the request-target is in authority-form or asterisk-form, the target
+// URI's combined path and query component is empty. Otherwise, the target
+// URI's combined path and query component is the request-target.
+//
+// The components of a reconstructed target URI, once determined as above,
+// can be recombined into absolute-URI form by concatenating the scheme,
+// "://", authority, and combined path and query component.
+//
+// If the target URI's authority component is empty and its URI scheme
+// requires a nonempty authority (as is the case for "http" and "https"), the
+// server can reject the request or determine whether a configured default
+// applies that is consistent with the incoming connection's context.
+//
+
+// ConnectionInfo is what the server knows about the connection on which a
+// request was received, which is used to reconstruct the target URI.
+type ConnectionInfo struct {
+	// Secure is true if the request was received over a secured connection,
+	// e.g. TLS.
+	Secure bool
+
+	// FixedScheme is the scheme configured for the server, or provided by a
+	// trusted gateway. If set, it is used instead of "http" or "https".
+	FixedScheme []byte
+
+	// FixedAuthority is the authority configured for the server. If set, it
+	// is used instead of the request-target and the Host field.
+	FixedAuthority []byte
+
+	// DefaultAuthority is used when the authority is empty otherwise.
+	DefaultAuthority []byte
+}
+
+// TargetUri reconstructs the target URI of req received on a connection
+// described by info. If the request-target is invalid, the error wraps
+// ErrInvalidRequestTarget. If the authority is empty, the error wraps
+// ErrEmptyAuthority.
+func (req Http11Request) TargetUri(info ConnectionInfo) (uri []byte, err error) {
+	target, err := req.ParseRequestTarget()
+	if err != nil {
+		return nil, err
+	}
+	if target.Form == AbsoluteForm {
+		return append([]byte{}, req.RequestTarget...), nil
+	}
+
+	scheme := []byte("http")
+	switch {
+	case len(info.FixedScheme) > 0:
+		scheme = info.FixedScheme
+	case info.Secure:
+		scheme = []byte("https")
+	}
+
+	var authority []byte
+	switch {
+	case len(info.FixedAuthority) > 0:
+		authority = info.FixedAuthority
+	case target.Form == AuthorityForm:
+		authority = req.RequestTarget
+	case isValidHost(req.FieldLines.Get("Host")):
+		authority = req.FieldLines.Get("Host")
+	}
+	if len(authority) == 0 {
+		authority = info.DefaultAuthority
+	}
+	if len(authority) == 0 {
+		return nil, newParseError(ErrEmptyAuthority, "Host", "authority of target URI is empty", nil)
+	}
+
+	uri = append(uri, scheme...)
+	uri = append(uri, "://"...)
+	uri = append(uri, authority...)
+	if target.Form == OriginForm {
+		uri = append(uri, req.RequestTarget...)
+	}
+	return uri, nil
+}
+
+// isValidHost reports whether a Host field value is valid and not empty.
+func isValidHost(fieldValue []byte) bool {
+	return len(fieldValue) > 0 && matchesAll(fieldValue, NewHostFinder())
+}
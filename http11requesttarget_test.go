@@ -0,0 +1,274 @@
+package http11p
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHttp11RequestParseRequestTarget(t *testing.T) {
+	tests := []struct {
+		testName         string
+		method           []byte
+		requestTarget    []byte
+		expectedErr      error
+		expectedForm     RequestTargetForm
+		expectedScheme   []byte
+		expectedHost     []byte
+		expectedPort     []byte
+		expectedPath     []byte
+		expectedSegments [][]byte
+		expectedQuery    []byte
+	}{
+		{
+			testName:         "origin-form",
+			method:           []byte("GET"),
+			requestTarget:    []byte("/where?q=now"),
+			expectedForm:     OriginForm,
+			expectedPath:     []byte("/where"),
+			expectedSegments: [][]byte{[]byte("where")},
+			expectedQuery:    []byte("q=now"),
+		},
+		{
+			testName:         "origin-form /",
+			method:           []byte("GET"),
+			requestTarget:    []byte("/"),
+			expectedForm:     OriginForm,
+			expectedPath:     []byte("/"),
+			expectedSegments: [][]byte{[]byte("")},
+		},
+		{
+			testName:         "origin-form with empty segments",
+			method:           []byte("GET"),
+			requestTarget:    []byte("/a//b/%2F?"),
+			expectedForm:     OriginForm,
+			expectedPath:     []byte("/a//b/%2F"),
+			expectedSegments: [][]byte{[]byte("a"), []byte(""), []byte("b"), []byte("%2F")},
+			expectedQuery:    []byte(""),
+		},
+		{
+			testName:         "absolute-form",
+			method:           []byte("GET"),
+			requestTarget:    []byte("http://www.example.org:8080/pub/WWW/TheProject.html?a=b"),
+			expectedForm:     AbsoluteForm,
+			expectedScheme:   []byte("http"),
+			expectedHost:     []byte("www.example.org"),
+			expectedPort:     []byte("8080"),
+			expectedPath:     []byte("/pub/WWW/TheProject.html"),
+			expectedSegments: [][]byte{[]byte("pub"), []byte("WWW"), []byte("TheProject.html")},
+			expectedQuery:    []byte("a=b"),
+		},
+		{
+			testName:       "absolute-form with empty path",
+			method:         []byte("GET"),
+			requestTarget:  []byte("http://[::1]"),
+			expectedForm:   AbsoluteForm,
+			expectedScheme: []byte("http"),
+			expectedHost:   []byte("[::1]"),
+		},
+		{
+			testName:         "absolute-form with IPv6 address",
+			method:           []byte("GET"),
+			requestTarget:    []byte("http://user@[2001:db8::1]:8080/x"),
+			expectedForm:     AbsoluteForm,
+			expectedScheme:   []byte("http"),
+			expectedHost:     []byte("[2001:db8::1]"),
+			expectedPort:     []byte("8080"),
+			expectedPath:     []byte("/x"),
+			expectedSegments: [][]byte{[]byte("x")},
+		},
+		{
+			testName:         "host:port is absolute-form except for CONNECT",
+			method:           []byte("GET"),
+			requestTarget:    []byte("www.example.com:80"),
+			expectedForm:     AbsoluteForm,
+			expectedScheme:   []byte("www.example.com"),
+			expectedPath:     []byte("80"),
+			expectedSegments: [][]byte{[]byte("80")},
+		},
+		{
+			testName:      "authority-form",
+			method:        []byte("CONNECT"),
+			requestTarget: []byte("www.example.com:80"),
+			expectedForm:  AuthorityForm,
+			expectedHost:  []byte("www.example.com"),
+			expectedPort:  []byte("80"),
+		},
+		{
+			testName:      "asterisk-form",
+			method:        []byte("OPTIONS"),
+			requestTarget: []byte("*"),
+			expectedForm:  AsteriskForm,
+		},
+		{
+			testName:      "CONNECT with origin-form",
+			method:        []byte("CONNECT"),
+			requestTarget: []byte("/"),
+			expectedErr:   ErrInvalidRequestTarget,
+		},
+		{
+			testName:      "fragment",
+			method:        []byte("GET"),
+			requestTarget: []byte("/a#b"),
+			expectedErr:   ErrInvalidRequestTarget,
+		},
+		{
+			testName:      "empty",
+			method:        []byte("GET"),
+			requestTarget: []byte(""),
+			expectedErr:   ErrInvalidRequestTarget,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			req := Http11Request{Method: test.method, RequestTarget: test.requestTarget}
+			target, err := req.ParseRequestTarget()
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("%v: expected: %v, actual: %v", test.testName, test.expectedErr, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+			equals(test.testName+" Form", t, test.expectedForm, target.Form)
+			if !byteEquals(test.expectedScheme, target.Scheme) {
+				t.Errorf("%v Scheme: expected: %q, actual: %q", test.testName, test.expectedScheme, target.Scheme)
+			}
+			if !byteEquals(test.expectedHost, target.Host) {
+				t.Errorf("%v Host: expected: %q, actual: %q", test.testName, test.expectedHost, target.Host)
+			}
+			if !byteEquals(test.expectedPort, target.Port) {
+				t.Errorf("%v Port: expected: %q, actual: %q", test.testName, test.expectedPort, target.Port)
+			}
+			if !byteEquals(test.expectedPath, target.Path) {
+				t.Errorf("%v Path: expected: %q, actual: %q", test.testName, test.expectedPath, target.Path)
+			}
+			equals(test.testName+" len(Segments)", t, len(test.expectedSegments), len(target.Segments))
+			for i := 0; i < len(test.expectedSegments) && i < len(target.Segments); i++ {
+				if !byteEquals(test.expectedSegments[i], target.Segments[i]) {
+					t.Errorf("%v Segments[%v]: expected: %q, actual: %q", test.testName, i, test.expectedSegments[i], target.Segments[i])
+				}
+			}
+			if !byteEquals(test.expectedQuery, target.Query) {
+				t.Errorf("%v Query: expected: %q, actual: %q", test.testName, test.expectedQuery, target.Query)
+			}
+			equals(test.testName+" has Query", t, test.expectedQuery != nil, target.Query != nil)
+		})
+	}
+}
+
+func TestHttp11RequestTargetUri(t *testing.T) {
+	tests := []struct {
+		testName      string
+		method        []byte
+		requestTarget []byte
+		host          []byte
+		info          ConnectionInfo
+		expectedErr   error
+		expected      []byte
+	}{
+		{
+			testName:      "origin-form",
+			method:        []byte("GET"),
+			requestTarget: []byte("/pub/WWW/TheProject.html?a=b"),
+			host:          []byte("www.example.org:8080"),
+			expected:      []byte("http://www.example.org:8080/pub/WWW/TheProject.html?a=b"),
+		},
+		{
+			testName:      "origin-form over a secured connection",
+			method:        []byte("GET"),
+			requestTarget: []byte("/"),
+			host:          []byte("www.example.org"),
+			info:          ConnectionInfo{Secure: true},
+			expected:      []byte("https://www.example.org/"),
+		},
+		{
+			testName:      "origin-form with IPv6 Host",
+			method:        []byte("GET"),
+			requestTarget: []byte("/"),
+			host:          []byte("[2001:db8::1]:8080"),
+			expected:      []byte("http://[2001:db8::1]:8080/"),
+		},
+		{
+			testName:      "authority-form with IPv6 address",
+			method:        []byte("CONNECT"),
+			requestTarget: []byte("[2001:db8::1]:443"),
+			expected:      []byte("http://[2001:db8::1]:443"),
+		},
+		{
+			testName:      "fixed scheme and authority",
+			method:        []byte("GET"),
+			requestTarget: []byte("/"),
+			host:          []byte("www.example.org"),
+			info:          ConnectionInfo{FixedScheme: []byte("https"), FixedAuthority: []byte("example.com")},
+			expected:      []byte("https://example.com/"),
+		},
+		{
+			testName:      "absolute-form ignores Host",
+			method:        []byte("GET"),
+			requestTarget: []byte("http://www.example.org/a"),
+			host:          []byte("example.com"),
+			info:          ConnectionInfo{Secure: true},
+			expected:      []byte("http://www.example.org/a"),
+		},
+		{
+			testName:      "absolute-form with IPv6 address",
+			method:        []byte("GET"),
+			requestTarget: []byte("http://[2001:db8::1]:8080/x"),
+			host:          []byte("[2001:db8::1]:8080"),
+			expected:      []byte("http://[2001:db8::1]:8080/x"),
+		},
+		{
+			testName:      "authority-form",
+			method:        []byte("CONNECT"),
+			requestTarget: []byte("www.example.com:80"),
+			host:          []byte("example.com"),
+			expected:      []byte("http://www.example.com:80"),
+		},
+		{
+			testName:      "asterisk-form",
+			method:        []byte("OPTIONS"),
+			requestTarget: []byte("*"),
+			host:          []byte("www.example.org:8080"),
+			expected:      []byte("http://www.example.org:8080"),
+		},
+		{
+			testName:      "no Host",
+			method:        []byte("GET"),
+			requestTarget: []byte("/"),
+			expectedErr:   ErrEmptyAuthority,
+		},
+		{
+			testName:      "invalid Host with default authority",
+			method:        []byte("GET"),
+			requestTarget: []byte("/"),
+			host:          []byte("a b"),
+			info:          ConnectionInfo{DefaultAuthority: []byte("localhost")},
+			expected:      []byte("http://localhost/"),
+		},
+		{
+			testName:      "invalid request-target",
+			method:        []byte("GET"),
+			requestTarget: []byte("a b"),
+			host:          []byte("www.example.org"),
+			expectedErr:   ErrInvalidRequestTarget,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			req := Http11Request{Method: test.method, RequestTarget: test.requestTarget}
+			if test.host != nil {
+				req.FieldLines.Add("Host", test.host)
+			}
+			uri, err := req.TargetUri(test.info)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("%v: expected: %v, actual: %v", test.testName, test.expectedErr, err)
+				return
+			}
+			if !byteEquals(test.expected, uri) {
+				t.Errorf("%v: expected: %q, actual: %q", test.testName, test.expected, uri)
+			}
+		})
+	}
+}
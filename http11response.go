@@ -1,7 +1,7 @@
 package http11p
 
 import (
-	"errors"
+	"io"
 
 	abnfp "github.com/um7a/abnf-parser"
 )
@@ -10,103 +10,289 @@ type Http11Response struct {
 	HttpVersion  []byte
 	StatusCode   []byte
 	ReasonPhrase []byte
-	FieldLines   []FieldLine
+	FieldLines   FieldSection
 	MessageBody  []byte
+
+	// Set when the message-body is sent with the chunked transfer coding.
+	// ChunkExtensions are the chunk-ext of all the chunks including
+	// last-chunk in order. Unmarshal sends MessageBody as a single chunk
+	// with all ChunkExtensions, so the chunk boundaries and the chunk each
+	// extension belonged to are not preserved.
+	ChunkExtensions []ChunkExtension
+	TrailerSection  FieldSection
+
+	// Set by ReadResponse instead of MessageBody.
+	Body io.ReadCloser
+
+	// Set when the response has neither Content-Length nor chunked
+	// Transfer-Encoding, so that its message-body is delimited by the
+	// closing of the connection.
+	CloseDelimited bool
 }
 
+// marshalStatusLine parses status-line. If it succeeds, resp.StatusCode is
+// 3DIGIT, so parseStatusCode of it does not fail.
 func marshalStatusLine(data []byte, resp *Http11Response) (remaining []byte, err error) {
 	remaining = data
 
 	resp.HttpVersion, remaining = abnfp.Parse(remaining, NewHttpVersionFinder())
 	if len(resp.HttpVersion) == 0 {
-		return data, errors.New("http-version not found")
+		return data, newParseError(ErrInvalidStatusLine, "HTTP-version", "http-version not found", remaining)
+	}
+	err = checkHttpVersion(resp.HttpVersion, data)
+	if err != nil {
+		return data, err
 	}
 
+	rest := remaining
 	sp, remaining := abnfp.Parse(remaining, abnfp.NewSpFinder())
 	if len(sp) == 0 {
-		return data, errors.New("SP after http-version not found")
+		return data, newParseError(ErrInvalidStatusLine, "SP", "SP after http-version not found", rest)
 	}
 
 	resp.StatusCode, remaining = abnfp.Parse(remaining, NewStatusCodeFinder())
 	if len(resp.StatusCode) == 0 {
-		return data, errors.New("status-code not found")
+		return data, newParseError(ErrInvalidStatusLine, "status-code", "status-code not found", remaining)
 	}
 
+	rest = remaining
 	sp, remaining = abnfp.Parse(remaining, abnfp.NewSpFinder())
 	if len(sp) == 0 {
-		return data, errors.New("SP after status-code not found")
+		return data, newParseError(ErrInvalidStatusLine, "SP", "SP after status-code not found", rest)
 	}
 
-	resp.ReasonPhrase, remaining = abnfp.Parse(remaining, NewReasonPhraseFinder())
-	if len(resp.ReasonPhrase) == 0 {
-		return data, errors.New("reason-phrase not found")
-	}
+	// [ reason-phrase ]
+	resp.ReasonPhrase, remaining = abnfp.Parse(
+		remaining,
+		abnfp.NewOptionalSequenceFinder(NewReasonPhraseFinder()),
+	)
 
 	return
 }
 
+// isStatusLinePrefix reports whether data is the part of status-line
+// received so far.
+func isStatusLinePrefix(data []byte) bool {
+	const versionLength = len("HTTP/1.1")
+	if len(data) <= versionLength {
+		return isHttpVersionPrefix(data)
+	}
+	if !isHttpVersionPrefix(data[:versionLength]) || data[versionLength] != ' ' {
+		return false
+	}
+	remaining := data[versionLength+1:]
+	for i, b := range remaining {
+		if i < 3 && (b < '0' || b > '9') {
+			return false
+		}
+		if (i == 3 && b != ' ') || (i > 3 && !isTextByte(b)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Marshal parses one response from data. It applies the limits of
+// DefaultParserOptions, e.g. a field line longer than 8 KiB is rejected with
+// ErrFieldLineTooLong; use MarshalWithOptions to change or remove them.
 func (resp *Http11Response) Marshal(data []byte) (err error) {
-	var result abnfp.ParseResult
-	remaining := data
+	_, err = resp.MarshalWithRemaining(data)
+	return err
+}
+
+// MarshalWithRemaining parses one response from data and returns the bytes
+// following its message-body, e.g. the next response on the connection.
+func (resp *Http11Response) MarshalWithRemaining(data []byte) (remaining []byte, err error) {
+	return resp.MarshalForRequest(data, nil)
+}
+
+// MarshalForRequest is like MarshalWithRemaining, but also applies the
+// message body length rules which depend on method, the method of the
+// request the response answers. A response to HEAD has no message-body, and
+// after a 2xx response to CONNECT the remaining data belongs to the tunnel.
+//
+// If the response cannot be parsed, the error is a *ParseError located in
+// data.
+func (resp *Http11Response) MarshalForRequest(data []byte, method []byte) (remaining []byte, err error) {
+	return resp.MarshalWithOptions(data, method, DefaultParserOptions())
+}
+
+// MarshalWithOptions is like MarshalForRequest, but parses data with options
+// instead of DefaultParserOptions. method can be nil if the request method
+// is unknown.
+func (resp *Http11Response) MarshalWithOptions(data []byte, method []byte, options ParserOptions) (
+	remaining []byte,
+	err error,
+) {
+	remaining = options.skipEmptyLines(data)
 
+	err = options.checkStartLine(lineLength(remaining), remaining)
+	if err != nil {
+		return data, locateError(err, data)
+	}
+
+	statusLine := remaining
 	remaining, err = marshalStatusLine(remaining, resp)
 	if err != nil {
-		return err
+		err = orIncomplete(err, "status-line", statusLine, NewStatusLineFinder(), isStatusLinePrefix)
+		return data, locateError(err, data)
 	}
 
-	crlf, remaining := abnfp.Parse(remaining, abnfp.NewCrLfFinder())
+	rest := remaining
+	crlf, remaining := abnfp.Parse(remaining, options.newEolFinder())
 	if len(crlf) == 0 {
-		return errors.New("CRLF after request-line not found")
+		err = newParseError(ErrInvalidStatusLine, "CRLF", "CRLF after status-line not found", rest)
+		err = orIncomplete(err, "status-line", statusLine, NewStatusLineFinder(), isStatusLinePrefix)
+		return data, locateError(err, data)
 	}
 
-	resp.FieldLines, remaining, err = marshalFieldLines(remaining)
+	fieldSection := remaining
+	resp.FieldLines, remaining, err = marshalFieldLines(remaining, options)
 	if err != nil {
-		return err
+		return data, locateError(err, data)
 	}
 
-	crlf, remaining = abnfp.Parse(remaining, abnfp.NewCrLfFinder())
+	rest = remaining
+	crlf, remaining = abnfp.Parse(remaining, options.newEolFinder())
 	if len(crlf) == 0 {
-		return errors.New("CRLF before message-body not found")
+		err = newParseError(ErrIncompleteMessage, "CRLF", "message ends before the end of the header section", rest)
+		return data, locateError(err, data)
+	}
+
+	framing, length, err := getResponseBodyFraming(method, resp.HttpVersion, resp.StatusCode, resp.FieldLines)
+	if err != nil {
+		pos := startPosition.advance(data[:len(data)-len(fieldSection)])
+		return data, locateFieldLineError(err, scanFieldLineStarts(fieldSection, pos, options))
 	}
+	resp.CloseDelimited = framing == framingUntilClose
+	resp.MessageBody, resp.ChunkExtensions, resp.TrailerSection, remaining, err =
+		marshalMessageBody(remaining, framing, length, options)
+	if err != nil {
+		return data, locateError(err, data)
+	}
+	return remaining, nil
+}
+
+// MarshalResponses parses pipelined responses from data until data is
+// consumed. If a response cannot be parsed, it returns the responses parsed
+// so far, the data starting from the failed response and the error.
+//
+// NOTE
+// A response without Content-Length and Transfer-Encoding is delimited by
+// the closing of the connection, so it consumes all the following data.
+// MarshalResponses does not know the request methods, so it cannot parse a
+// response to HEAD or CONNECT; use MarshalResponsesForRequests instead.
+func MarshalResponses(data []byte) (resps []Http11Response, remaining []byte, err error) {
+	return MarshalResponsesWithOptions(data, DefaultParserOptions())
+}
+
+// MarshalResponsesWithOptions is like MarshalResponses, but parses data with
+// options instead of DefaultParserOptions.
+func MarshalResponsesWithOptions(data []byte, options ParserOptions) (
+	resps []Http11Response,
+	remaining []byte,
+	err error,
+) {
+	return marshalResponses(data, nil, options)
+}
 
-	resp.MessageBody = result.Remaining
-	return nil
+// MarshalResponsesForRequests is like MarshalResponses, but also applies the
+// message body length rules which depend on the methods of the requests, as
+// MarshalForRequest does. methods are the methods of the pipelined requests
+// in order. The interim (1xx) responses answer the same request as the
+// final response which follows them. The responses after the last method
+// are parsed as if the method is unknown.
+//
+// Parsing stops after a 101 response or a 2xx response to CONNECT, and the
+// remaining data belongs to the new protocol or the tunnel.
+func MarshalResponsesForRequests(data []byte, methods [][]byte) (
+	resps []Http11Response,
+	remaining []byte,
+	err error,
+) {
+	return marshalResponses(data, methods, DefaultParserOptions())
 }
 
+func marshalResponses(data []byte, methods [][]byte, options ParserOptions) (
+	resps []Http11Response,
+	remaining []byte,
+	err error,
+) {
+	resps = []Http11Response{}
+	remaining = data
+	for len(remaining) > 0 {
+		var method []byte
+		if len(methods) > 0 {
+			method = methods[0]
+		}
+		var resp Http11Response
+		remaining, err = resp.MarshalWithOptions(remaining, method, options)
+		if err != nil {
+			return resps, remaining, locateError(err, data)
+		}
+		resps = append(resps, resp)
+
+		code, _ := parseStatusCode(resp.StatusCode)
+		if code == StatusSwitchingProtocols || (Method(method) == MethodConnect && code.IsSuccessful()) {
+			break
+		}
+		if !code.IsInformational() && len(methods) > 0 {
+			methods = methods[1:]
+		}
+	}
+	return resps, remaining, nil
+}
+
+// Unmarshal serializes resp as a response message.
+//
+// NOTE
+// A 1xx, 204 or 304 response has no message-body, so Unmarshal does not send
+// MessageBody of such a response even if it is not empty. MessageBody of an
+// HTTP/1.0 response is sent as is even with chunked Transfer-Encoding.
 func (resp Http11Response) Unmarshal() (data []byte) {
 	sp := []byte(" ")
 	crlf := []byte("\r\n")
-	colon := []byte(":")
 
 	data = append(data, resp.HttpVersion...)
 	data = append(data, sp...)
 	data = append(data, resp.StatusCode...)
 	data = append(data, sp...)
-	data = append(data, resp.ReasonPhrase...)
+	data = append(data, resp.reasonPhrase()...)
 	data = append(data, crlf...)
-	for _, fieldLine := range resp.FieldLines {
-		data = append(data, fieldLine.FieldName...)
-		data = append(data, colon...)
-		data = append(data, sp...)
-		data = append(data, fieldLine.FieldValue...)
-		data = append(data, crlf...)
-	}
+	data = append(data, unmarshalFieldLines(resp.FieldLines)...)
 	data = append(data, crlf...)
 
+	framing, _, err := getResponseBodyFraming(nil, resp.HttpVersion, resp.StatusCode, resp.FieldLines)
+	if err == nil && framing == framingNone {
+		return
+	}
+	if framing == framingChunked {
+		data = append(data, unmarshalChunkedBody(resp.MessageBody, resp.ChunkExtensions, resp.TrailerSection)...)
+		return
+	}
 	data = append(data, resp.MessageBody...)
 	return
 }
 
+// reasonPhrase returns ReasonPhrase, or the registered reason phrase of the
+// status code if ReasonPhrase is nil. An empty ReasonPhrase, e.g. one parsed
+// from "HTTP/1.1 200 \r\n", is kept so that the response round-trips.
+func (resp Http11Response) reasonPhrase() []byte {
+	if resp.ReasonPhrase != nil {
+		return resp.ReasonPhrase
+	}
+	code, ok := parseStatusCode(resp.StatusCode)
+	if !ok {
+		return resp.ReasonPhrase
+	}
+	return []byte(code.ReasonPhrase())
+}
+
 func (resp Http11Response) String() string {
 	bytes := resp.Unmarshal()
 	return string(bytes)
 }
 
 func (resp Http11Response) GetHeader(name string) []byte {
-	for _, fieldLine := range resp.FieldLines {
-		if string(fieldLine.FieldName) == name {
-			return fieldLine.FieldValue
-		}
-	}
-	return nil
+	return resp.FieldLines.Get(name)
 }
@@ -13,6 +13,12 @@ type TestCaseForHttp11ResponseMarshal struct {
 	expectedMessageBody  []byte
 }
 
+type TestCaseForHttp11ResponseRoundTrip struct {
+	testName            string
+	data                []byte
+	expectedMessageBody []byte
+}
+
 type TestCaseForHttp11ResponseUnmarshal struct {
 	testName      string
 	resp          Http11Response
@@ -102,6 +108,47 @@ func execTestForHttp11ResponseMarshal(tests []TestCaseForHttp11ResponseMarshal,
 					return
 				}
 			}
+
+			equals = byteEquals(testCase.expectedMessageBody, resp.MessageBody)
+			if !equals {
+				t.Errorf("expectedMessageBody: %v, actual: %v",
+					testCase.expectedMessageBody, resp.MessageBody)
+				return
+			}
+		})
+	}
+}
+
+func execTestForHttp11ResponseRoundTrip(tests []TestCaseForHttp11ResponseRoundTrip, t *testing.T) {
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			var resp Http11Response
+			err := resp.Marshal(testCase.data)
+			if err != nil {
+				t.Errorf("Failed to marshal Http/1.1 Response: %v", err.Error())
+				return
+			}
+			if !byteEquals(testCase.expectedMessageBody, resp.MessageBody) {
+				t.Errorf("expectedMessageBody: %q, actual: %q",
+					testCase.expectedMessageBody, resp.MessageBody)
+				return
+			}
+
+			actualBytes := resp.Unmarshal()
+			if !byteEquals(testCase.data, actualBytes) {
+				t.Errorf("expected: %q, actual: %q", testCase.data, actualBytes)
+				return
+			}
+
+			var again Http11Response
+			err = again.Marshal(actualBytes)
+			if err != nil {
+				t.Errorf("Failed to marshal unmarshaled Http/1.1 Response: %v", err.Error())
+				return
+			}
+			if again.String() != resp.String() {
+				t.Errorf("expected: %q, actual: %q", resp.String(), again.String())
+			}
 		})
 	}
 }
@@ -178,10 +225,142 @@ func TestHttp11ResponseMarshal(t *testing.T) {
 			expectedFieldLines:   []FieldLine{},
 			expectedMessageBody:  []byte{},
 		},
+		{
+			testName: "data: []byte(\"HTTP/1.1 204 \\r\\n\\r\\n\")",
+			data: []byte(
+				"HTTP/1.1 204 \r\n" +
+					"\r\n",
+			),
+			err:                  false,
+			expectedHttpVersion:  []byte("HTTP/1.1"),
+			expectedStatusCode:   []byte("204"),
+			expectedReasonPhrase: []byte{},
+			expectedFieldLines:   []FieldLine{},
+			expectedMessageBody:  []byte{},
+		},
+		{
+			testName: "data: []byte(\"HTTP/1.1 200\\r\\n\\r\\n\")",
+			data: []byte(
+				"HTTP/1.1 200\r\n" +
+					"\r\n",
+			),
+			err: true,
+		},
 	}
 	execTestForHttp11ResponseMarshal(tests, t)
 }
 
+func TestHttp11ResponseRoundTrip(t *testing.T) {
+	tests := []TestCaseForHttp11ResponseRoundTrip{
+		{
+			testName: "200 with Content-Length",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Date: Mon, 27 Jul 2009 12:28:53 GMT\r\n" +
+					"Server: Apache\r\n" +
+					"Last-Modified: Wed, 22 Jul 2009 19:15:56 GMT\r\n" +
+					"ETag: \"34aa387-d-1568eb00\"\r\n" +
+					"Accept-Ranges: bytes\r\n" +
+					"Content-Length: 51\r\n" +
+					"Vary: Accept-Encoding\r\n" +
+					"Content-Type: text/plain\r\n" +
+					"\r\n" +
+					"Hello World! My content includes a trailing CRLF.\r\n",
+			),
+			expectedMessageBody: []byte("Hello World! My content includes a trailing CRLF.\r\n"),
+		},
+		{
+			testName: "200 with JSON body",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Type: application/json; charset=utf-8\r\n" +
+					"Content-Length: 25\r\n" +
+					"Cache-Control: no-store\r\n" +
+					"\r\n" +
+					"{\"id\":1,\"name\":\"example\"}",
+			),
+			expectedMessageBody: []byte("{\"id\":1,\"name\":\"example\"}"),
+		},
+		{
+			testName: "200 with chunked body and trailer-section",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Type: text/plain\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"Trailer: Expires\r\n" +
+					"\r\n" +
+					"1a;foo=bar\r\n" +
+					"abcdefghijklmnopqrstuvwxyz\r\n" +
+					"0\r\n" +
+					"Expires: Wed, 21 Oct 2015 07:28:00 GMT\r\n" +
+					"\r\n",
+			),
+			expectedMessageBody: []byte("abcdefghijklmnopqrstuvwxyz"),
+		},
+		{
+			testName: "404 with HTML body",
+			data: []byte(
+				"HTTP/1.1 404 Not Found\r\n" +
+					"Content-Type: text/html\r\n" +
+					"Content-Length: 48\r\n" +
+					"\r\n" +
+					"<html><body><h1>Not Found</h1></body></html>\r\n\r\n",
+			),
+			expectedMessageBody: []byte("<html><body><h1>Not Found</h1></body></html>\r\n\r\n"),
+		},
+		{
+			testName: "301 without body",
+			data: []byte(
+				"HTTP/1.1 301 Moved Permanently\r\n" +
+					"Location: https://www.example.com/\r\n" +
+					"Content-Length: 0\r\n" +
+					"\r\n",
+			),
+			expectedMessageBody: []byte{},
+		},
+		{
+			testName: "204 without body",
+			data: []byte(
+				"HTTP/1.1 204 No Content\r\n" +
+					"Date: Mon, 27 Jul 2009 12:28:53 GMT\r\n" +
+					"\r\n",
+			),
+			expectedMessageBody: []byte{},
+		},
+		{
+			testName: "200 without reason-phrase",
+			data: []byte(
+				"HTTP/1.1 200 \r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg",
+			),
+			expectedMessageBody: []byte("abcdefg"),
+		},
+		{
+			testName: "HTTP/1.0 close-delimited body",
+			data: []byte(
+				"HTTP/1.0 200 OK\r\n" +
+					"Content-Type: text/plain\r\n" +
+					"\r\n" +
+					"abcdefg",
+			),
+			expectedMessageBody: []byte("abcdefg"),
+		},
+		{
+			testName: "HTTP/1.0 with Transfer-Encoding",
+			data: []byte(
+				"HTTP/1.0 200 OK\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"\r\n" +
+					"3\r\nabc\r\n0\r\n\r\n",
+			),
+			expectedMessageBody: []byte("3\r\nabc\r\n0\r\n\r\n"),
+		},
+	}
+	execTestForHttp11ResponseRoundTrip(tests, t)
+}
+
 func TestHttp11ResponseUnMarshal(t *testing.T) {
 	tests := []TestCaseForHttp11ResponseUnmarshal{
 		{
@@ -219,6 +398,63 @@ func TestHttp11ResponseUnMarshal(t *testing.T) {
 					"abcdefg",
 			),
 		},
+		{
+			testName: "body of 304 is not sent",
+			resp: Http11Response{
+				HttpVersion:  []byte("HTTP/1.1"),
+				StatusCode:   []byte("304"),
+				ReasonPhrase: []byte("Not Modified"),
+				FieldLines: []FieldLine{
+					{
+						FieldName:  []byte("Content-Length"),
+						FieldValue: []byte("7"),
+					},
+				},
+				MessageBody: []byte("abcdefg"),
+			},
+			expectedBytes: []byte(
+				"HTTP/1.1 304 Not Modified\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n",
+			),
+		},
+		{
+			testName: "without reason-phrase",
+			resp: Http11Response{
+				HttpVersion:  []byte("HTTP/1.1"),
+				StatusCode:   []byte("204"),
+				ReasonPhrase: []byte{},
+				FieldLines:   []FieldLine{},
+			},
+			expectedBytes: []byte("HTTP/1.1 204 \r\n" +
+				"\r\n"),
+		},
+		{
+			testName: "reason-phrase not set",
+			resp: Http11Response{
+				HttpVersion: []byte("HTTP/1.1"),
+				StatusCode:  []byte("204"),
+				FieldLines:  []FieldLine{},
+			},
+			expectedBytes: []byte("HTTP/1.1 204 No Content\r\n" +
+				"\r\n"),
+		},
+		{
+			testName: "reason-phrase of unregistered status-code not set",
+			resp: Http11Response{
+				HttpVersion: []byte("HTTP/1.1"),
+				StatusCode:  []byte("299"),
+				FieldLines:  []FieldLine{},
+			},
+			expectedBytes: []byte("HTTP/1.1 299 \r\n" +
+				"\r\n"),
+		},
+		{
+			testName: "NewHttp11Response",
+			resp:     NewHttp11Response(StatusNotFound),
+			expectedBytes: []byte("HTTP/1.1 404 Not Found\r\n" +
+				"\r\n"),
+		},
 	}
 	execTestForHttp11ResponseUnmarshal(tests, t)
 }
@@ -296,6 +532,302 @@ func TestHttp11ResponseGetHeader(t *testing.T) {
 			fieldName:          "CacheControl",
 			expectedFieldValue: []byte{},
 		},
+		{
+			testName: "existing field in different case",
+			resp: Http11Response{
+				HttpVersion:  []byte("HTTP/1.1"),
+				StatusCode:   []byte("200"),
+				ReasonPhrase: []byte("OK"),
+				FieldLines: []FieldLine{
+					{
+						FieldName:  []byte("Content-Length"),
+						FieldValue: []byte("7"),
+					},
+				},
+				MessageBody: []byte("abcdefg"),
+			},
+			fieldName:          "content-length",
+			expectedFieldValue: []byte("7"),
+		},
 	}
 	execTestForHttp11ResponseGetHeader(tests, t)
 }
+
+func TestMarshalResponses(t *testing.T) {
+	type TestCaseMarshalResponses struct {
+		testName            string
+		data                []byte
+		err                 bool
+		expectedStatusCodes []string
+		expectedRemaining   []byte
+	}
+
+	tests := []TestCaseMarshalResponses{
+		{
+			testName:            "data: []byte{}",
+			data:                []byte{},
+			err:                 false,
+			expectedStatusCodes: []string{},
+			expectedRemaining:   []byte{},
+		},
+		{
+			testName: "pipelined responses",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg" +
+					"HTTP/1.1 404 Not Found\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"\r\n" +
+					"7\r\nabcdefg\r\n0\r\n\r\n" +
+					"HTTP/1.1 200 OK\r\n" +
+					"\r\n" +
+					"HTTP/1.1 200 OK\r\n",
+			),
+			err:                 false,
+			expectedStatusCodes: []string{"200", "404", "200"},
+			expectedRemaining:   []byte{},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			resps, remaining, err := MarshalResponses(testCase.data)
+			if err != nil && !testCase.err {
+				t.Errorf("Failed to marshal Http/1.1 Responses: %v", err.Error())
+				return
+			}
+			if err == nil && testCase.err {
+				t.Errorf("Unexpectedly marshal Http/1.1 Responses successfully: %v", resps)
+				return
+			}
+			if len(testCase.expectedStatusCodes) != len(resps) {
+				t.Errorf("len(expectedStatusCodes): %v, len(actual): %v",
+					len(testCase.expectedStatusCodes), len(resps))
+				return
+			}
+			for i, expected := range testCase.expectedStatusCodes {
+				equals(testCase.testName, t, expected, string(resps[i].StatusCode))
+			}
+			if !byteEquals(testCase.expectedRemaining, remaining) {
+				t.Errorf("expectedRemaining: %s, actual: %s", testCase.expectedRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestMarshalResponsesForRequests(t *testing.T) {
+	type TestCaseMarshalResponsesForRequests struct {
+		testName              string
+		data                  []byte
+		methods               [][]byte
+		expectedStatusCodes   []string
+		expectedMessageBodies []string
+		expectedRemaining     []byte
+	}
+
+	tests := []TestCaseMarshalResponsesForRequests{
+		{
+			testName: "response to HEAD",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg",
+			),
+			methods:               [][]byte{[]byte("HEAD"), []byte("GET")},
+			expectedStatusCodes:   []string{"200", "200"},
+			expectedMessageBodies: []string{"", "abcdefg"},
+			expectedRemaining:     []byte{},
+		},
+		{
+			testName: "interim response",
+			data: []byte(
+				"HTTP/1.1 100 Continue\r\n" +
+					"\r\n" +
+					"HTTP/1.1 204 No Content\r\n" +
+					"\r\n" +
+					"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 3\r\n" +
+					"\r\n",
+			),
+			methods:               [][]byte{[]byte("POST"), []byte("HEAD")},
+			expectedStatusCodes:   []string{"100", "204", "200"},
+			expectedMessageBodies: []string{"", "", ""},
+			expectedRemaining:     []byte{},
+		},
+		{
+			testName: "tunnel",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"\r\n" +
+					"tunnel data",
+			),
+			methods:               [][]byte{[]byte("CONNECT")},
+			expectedStatusCodes:   []string{"200"},
+			expectedMessageBodies: []string{""},
+			expectedRemaining:     []byte("tunnel data"),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			resps, remaining, err := MarshalResponsesForRequests(testCase.data, testCase.methods)
+			if err != nil {
+				t.Errorf("Failed to marshal Http/1.1 Responses: %v", err.Error())
+				return
+			}
+			if len(testCase.expectedStatusCodes) != len(resps) {
+				t.Errorf("len(expectedStatusCodes): %v, len(actual): %v",
+					len(testCase.expectedStatusCodes), len(resps))
+				return
+			}
+			for i, expected := range testCase.expectedStatusCodes {
+				equals(testCase.testName+" StatusCode", t, expected, string(resps[i].StatusCode))
+				equals(testCase.testName+" MessageBody", t, testCase.expectedMessageBodies[i], string(resps[i].MessageBody))
+			}
+			if !byteEquals(testCase.expectedRemaining, remaining) {
+				t.Errorf("expectedRemaining: %s, actual: %s", testCase.expectedRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestHttp11ResponseMarshalForRequest(t *testing.T) {
+	type TestCaseHttp11ResponseMarshalForRequest struct {
+		testName            string
+		data                []byte
+		method              []byte
+		err                 bool
+		expectedMessageBody []byte
+		expectedRemaining   []byte
+	}
+
+	tests := []TestCaseHttp11ResponseMarshalForRequest{
+		{
+			testName: "response to HEAD",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"HTTP/1.1 200 OK\r\n" +
+					"\r\n",
+			),
+			method:              []byte("HEAD"),
+			err:                 false,
+			expectedMessageBody: []byte{},
+			expectedRemaining:   []byte("HTTP/1.1 200 OK\r\n\r\n"),
+		},
+		{
+			testName: "304 response to GET",
+			data: []byte(
+				"HTTP/1.1 304 Not Modified\r\n" +
+					"Transfer-Encoding: chunked\r\n" +
+					"\r\n",
+			),
+			method:              []byte("GET"),
+			err:                 false,
+			expectedMessageBody: []byte{},
+			expectedRemaining:   []byte{},
+		},
+		{
+			testName: "2xx response to CONNECT",
+			data: []byte(
+				"HTTP/1.1 200 Connection Established\r\n" +
+					"Content-Length: 3\r\n" +
+					"\r\n" +
+					"\x16\x03\x01",
+			),
+			method:              []byte("CONNECT"),
+			err:                 false,
+			expectedMessageBody: []byte{},
+			expectedRemaining:   []byte("\x16\x03\x01"),
+		},
+		{
+			testName: "response to GET",
+			data: []byte(
+				"HTTP/1.1 200 OK\r\n" +
+					"Content-Length: 7\r\n" +
+					"\r\n" +
+					"abcdefg",
+			),
+			method:              []byte("GET"),
+			err:                 false,
+			expectedMessageBody: []byte("abcdefg"),
+			expectedRemaining:   []byte{},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			var resp Http11Response
+			remaining, err := resp.MarshalForRequest(testCase.data, testCase.method)
+			if err != nil && !testCase.err {
+				t.Errorf("Failed to marshal Http/1.1 Response: %v", err.Error())
+				return
+			}
+			if err == nil && testCase.err {
+				t.Errorf("Unexpectedly marshal Http/1.1 Response successfully: %v", resp)
+				return
+			}
+			if !byteEquals(testCase.expectedMessageBody, resp.MessageBody) {
+				t.Errorf("expectedMessageBody: %s, actual: %s", testCase.expectedMessageBody, resp.MessageBody)
+			}
+			if !byteEquals(testCase.expectedRemaining, remaining) {
+				t.Errorf("expectedRemaining: %s, actual: %s", testCase.expectedRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestHttp11ResponseCloseDelimited(t *testing.T) {
+	type TestCaseHttp11ResponseCloseDelimited struct {
+		testName               string
+		data                   []byte
+		expectedCloseDelimited bool
+	}
+
+	tests := []TestCaseHttp11ResponseCloseDelimited{
+		{
+			testName:               "without Content-Length and Transfer-Encoding",
+			data:                   []byte("HTTP/1.0 200 OK\r\n\r\nabcdefg"),
+			expectedCloseDelimited: true,
+		},
+		{
+			testName:               "without Content-Length and Transfer-Encoding, empty body",
+			data:                   []byte("HTTP/1.0 200 OK\r\n\r\n"),
+			expectedCloseDelimited: true,
+		},
+		{
+			testName:               "Transfer-Encoding without chunked",
+			data:                   []byte("HTTP/1.1 200 OK\r\nTransfer-Encoding: gzip\r\n\r\nabcdefg"),
+			expectedCloseDelimited: true,
+		},
+		{
+			testName:               "Content-Length: 0",
+			data:                   []byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"),
+			expectedCloseDelimited: false,
+		},
+		{
+			testName:               "204",
+			data:                   []byte("HTTP/1.1 204 No Content\r\n\r\n"),
+			expectedCloseDelimited: false,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			var resp Http11Response
+			err := resp.Marshal(testCase.data)
+			if err != nil {
+				t.Errorf("Failed to marshal Http/1.1 Response: %v", err.Error())
+				return
+			}
+			equals(testCase.testName, t, testCase.expectedCloseDelimited, resp.CloseDelimited)
+		})
+	}
+}
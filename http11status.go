@@ -0,0 +1,238 @@
+package http11p
+
+import (
+	"strconv"
+)
+
+// RFC9110 - 15. Status Codes
+//
+// The status code of a response is a three-digit integer code that
+// describes the result of the request and the semantics of the response,
+// including whether the request was successful and what content is enclosed
+// (if any). All valid status codes are within the range of 100 to 599,
+// inclusive.
+//
+// The first digit of the status code defines the class of response. The
+// last two digits do not have any categorization role. There are five
+// values for the first digit:
+//
+//  - 1xx (Informational): The request was received, continuing process
+//  - 2xx (Successful): The request was successfully received, understood,
+//    and accepted
+//  - 3xx (Redirection): Further action needs to be taken in order to
+//    complete the request
+//  - 4xx (Client Error): The request contains bad syntax or cannot be
+//    fulfilled
+//  - 5xx (Server Error): The server failed to fulfill an apparently valid
+//    request
+//
+
+type StatusCode int
+
+// The status codes in the IANA HTTP Status Code Registry.
+const (
+	StatusContinue           StatusCode = 100
+	StatusSwitchingProtocols StatusCode = 101
+	StatusProcessing         StatusCode = 102
+	StatusEarlyHints         StatusCode = 103
+
+	StatusOk                          StatusCode = 200
+	StatusCreated                     StatusCode = 201
+	StatusAccepted                    StatusCode = 202
+	StatusNonAuthoritativeInformation StatusCode = 203
+	StatusNoContent                   StatusCode = 204
+	StatusResetContent                StatusCode = 205
+	StatusPartialContent              StatusCode = 206
+	StatusMultiStatus                 StatusCode = 207
+	StatusAlreadyReported             StatusCode = 208
+	StatusImUsed                      StatusCode = 226
+
+	StatusMultipleChoices   StatusCode = 300
+	StatusMovedPermanently  StatusCode = 301
+	StatusFound             StatusCode = 302
+	StatusSeeOther          StatusCode = 303
+	StatusNotModified       StatusCode = 304
+	StatusUseProxy          StatusCode = 305
+	StatusTemporaryRedirect StatusCode = 307
+	StatusPermanentRedirect StatusCode = 308
+
+	StatusBadRequest                  StatusCode = 400
+	StatusUnauthorized                StatusCode = 401
+	StatusPaymentRequired             StatusCode = 402
+	StatusForbidden                   StatusCode = 403
+	StatusNotFound                    StatusCode = 404
+	StatusMethodNotAllowed            StatusCode = 405
+	StatusNotAcceptable               StatusCode = 406
+	StatusProxyAuthenticationRequired StatusCode = 407
+	StatusRequestTimeout              StatusCode = 408
+	StatusConflict                    StatusCode = 409
+	StatusGone                        StatusCode = 410
+	StatusLengthRequired              StatusCode = 411
+	StatusPreconditionFailed          StatusCode = 412
+	StatusContentTooLarge             StatusCode = 413
+	StatusUriTooLong                  StatusCode = 414
+	StatusUnsupportedMediaType        StatusCode = 415
+	StatusRangeNotSatisfiable         StatusCode = 416
+	StatusExpectationFailed           StatusCode = 417
+	StatusMisdirectedRequest          StatusCode = 421
+	StatusUnprocessableContent        StatusCode = 422
+	StatusLocked                      StatusCode = 423
+	StatusFailedDependency            StatusCode = 424
+	StatusTooEarly                    StatusCode = 425
+	StatusUpgradeRequired             StatusCode = 426
+	StatusPreconditionRequired        StatusCode = 428
+	StatusTooManyRequests             StatusCode = 429
+	StatusRequestHeaderFieldsTooLarge StatusCode = 431
+	StatusUnavailableForLegalReasons  StatusCode = 451
+
+	StatusInternalServerError           StatusCode = 500
+	StatusNotImplemented                StatusCode = 501
+	StatusBadGateway                    StatusCode = 502
+	StatusServiceUnavailable            StatusCode = 503
+	StatusGatewayTimeout                StatusCode = 504
+	StatusHttpVersionNotSupported       StatusCode = 505
+	StatusVariantAlsoNegotiates         StatusCode = 506
+	StatusInsufficientStorage           StatusCode = 507
+	StatusLoopDetected                  StatusCode = 508
+	StatusNotExtended                   StatusCode = 510
+	StatusNetworkAuthenticationRequired StatusCode = 511
+)
+
+// reasonPhrases is the reason phrases of the status codes in the IANA HTTP
+// Status Code Registry.
+var reasonPhrases = map[StatusCode]string{
+	StatusContinue:                      "Continue",
+	StatusSwitchingProtocols:            "Switching Protocols",
+	StatusProcessing:                    "Processing",
+	StatusEarlyHints:                    "Early Hints",
+	StatusOk:                            "OK",
+	StatusCreated:                       "Created",
+	StatusAccepted:                      "Accepted",
+	StatusNonAuthoritativeInformation:   "Non-Authoritative Information",
+	StatusNoContent:                     "No Content",
+	StatusResetContent:                  "Reset Content",
+	StatusPartialContent:                "Partial Content",
+	StatusMultiStatus:                   "Multi-Status",
+	StatusAlreadyReported:               "Already Reported",
+	StatusImUsed:                        "IM Used",
+	StatusMultipleChoices:               "Multiple Choices",
+	StatusMovedPermanently:              "Moved Permanently",
+	StatusFound:                         "Found",
+	StatusSeeOther:                      "See Other",
+	StatusNotModified:                   "Not Modified",
+	StatusUseProxy:                      "Use Proxy",
+	StatusTemporaryRedirect:             "Temporary Redirect",
+	StatusPermanentRedirect:             "Permanent Redirect",
+	StatusBadRequest:                    "Bad Request",
+	StatusUnauthorized:                  "Unauthorized",
+	StatusPaymentRequired:               "Payment Required",
+	StatusForbidden:                     "Forbidden",
+	StatusNotFound:                      "Not Found",
+	StatusMethodNotAllowed:              "Method Not Allowed",
+	StatusNotAcceptable:                 "Not Acceptable",
+	StatusProxyAuthenticationRequired:   "Proxy Authentication Required",
+	StatusRequestTimeout:                "Request Timeout",
+	StatusConflict:                      "Conflict",
+	StatusGone:                          "Gone",
+	StatusLengthRequired:                "Length Required",
+	StatusPreconditionFailed:            "Precondition Failed",
+	StatusContentTooLarge:               "Content Too Large",
+	StatusUriTooLong:                    "URI Too Long",
+	StatusUnsupportedMediaType:          "Unsupported Media Type",
+	StatusRangeNotSatisfiable:           "Range Not Satisfiable",
+	StatusExpectationFailed:             "Expectation Failed",
+	StatusMisdirectedRequest:            "Misdirected Request",
+	StatusUnprocessableContent:          "Unprocessable Content",
+	StatusLocked:                        "Locked",
+	StatusFailedDependency:              "Failed Dependency",
+	StatusTooEarly:                      "Too Early",
+	StatusUpgradeRequired:               "Upgrade Required",
+	StatusPreconditionRequired:          "Precondition Required",
+	StatusTooManyRequests:               "Too Many Requests",
+	StatusRequestHeaderFieldsTooLarge:   "Request Header Fields Too Large",
+	StatusUnavailableForLegalReasons:    "Unavailable For Legal Reasons",
+	StatusInternalServerError:           "Internal Server Error",
+	StatusNotImplemented:                "Not Implemented",
+	StatusBadGateway:                    "Bad Gateway",
+	StatusServiceUnavailable:            "Service Unavailable",
+	StatusGatewayTimeout:                "Gateway Timeout",
+	StatusHttpVersionNotSupported:       "HTTP Version Not Supported",
+	StatusVariantAlsoNegotiates:         "Variant Also Negotiates",
+	StatusInsufficientStorage:           "Insufficient Storage",
+	StatusLoopDetected:                  "Loop Detected",
+	StatusNotExtended:                   "Not Extended",
+	StatusNetworkAuthenticationRequired: "Network Authentication Required",
+}
+
+// ReasonPhrase returns the registered reason phrase of code, or "" if code
+// is not registered.
+func (code StatusCode) ReasonPhrase() string {
+	return reasonPhrases[code]
+}
+
+// IsValid reports whether code is within the range of 100 to 599.
+func (code StatusCode) IsValid() bool {
+	return code >= 100 && code <= 599
+}
+
+// IsInformational reports whether code is 1xx.
+func (code StatusCode) IsInformational() bool {
+	return code >= 100 && code <= 199
+}
+
+// IsSuccessful reports whether code is 2xx.
+func (code StatusCode) IsSuccessful() bool {
+	return code >= 200 && code <= 299
+}
+
+// IsRedirection reports whether code is 3xx.
+func (code StatusCode) IsRedirection() bool {
+	return code >= 300 && code <= 399
+}
+
+// IsClientError reports whether code is 4xx.
+func (code StatusCode) IsClientError() bool {
+	return code >= 400 && code <= 499
+}
+
+// IsServerError reports whether code is 5xx.
+func (code StatusCode) IsServerError() bool {
+	return code >= 500 && code <= 599
+}
+
+// Bytes returns code as status-code.
+func (code StatusCode) Bytes() []byte {
+	return []byte(strconv.Itoa(int(code)))
+}
+
+// parseStatusCode converts status-code to StatusCode. It returns false if
+// statusCode is not 3DIGIT.
+func parseStatusCode(statusCode []byte) (code StatusCode, ok bool) {
+	if !matchesAll(statusCode, NewStatusCodeFinder()) {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(string(statusCode))
+	return StatusCode(n), true
+}
+
+// Status returns resp.StatusCode as StatusCode. If it is not 3DIGIT or not
+// within the range of 100 to 599, the error is a *ParseError wrapping
+// ErrInvalidStatusLine.
+func (resp Http11Response) Status() (StatusCode, error) {
+	code, ok := parseStatusCode(resp.StatusCode)
+	if !ok || !code.IsValid() {
+		return 0, newParseError(ErrInvalidStatusLine, "status-code", "invalid status-code", nil)
+	}
+	return code, nil
+}
+
+// NewHttp11Response returns an HTTP/1.1 response with code and its
+// registered reason phrase.
+func NewHttp11Response(code StatusCode) Http11Response {
+	return Http11Response{
+		HttpVersion:  []byte("HTTP/1.1"),
+		StatusCode:   code.Bytes(),
+		ReasonPhrase: []byte(code.ReasonPhrase()),
+		FieldLines:   FieldSection{},
+	}
+}
@@ -0,0 +1,67 @@
+package http11p
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatusCodeClass(t *testing.T) {
+	tests := []struct {
+		code                  StatusCode
+		expectedValid         bool
+		expectedInformational bool
+		expectedSuccessful    bool
+		expectedRedirection   bool
+		expectedClientError   bool
+		expectedServerError   bool
+	}{
+		{code: 99},
+		{code: StatusContinue, expectedValid: true, expectedInformational: true},
+		{code: StatusOk, expectedValid: true, expectedSuccessful: true},
+		{code: StatusPermanentRedirect, expectedValid: true, expectedRedirection: true},
+		{code: 499, expectedValid: true, expectedClientError: true},
+		{code: 599, expectedValid: true, expectedServerError: true},
+		{code: 600},
+	}
+
+	for _, test := range tests {
+		name := string(test.code.Bytes())
+		equals(name+" IsValid", t, test.expectedValid, test.code.IsValid())
+		equals(name+" IsInformational", t, test.expectedInformational, test.code.IsInformational())
+		equals(name+" IsSuccessful", t, test.expectedSuccessful, test.code.IsSuccessful())
+		equals(name+" IsRedirection", t, test.expectedRedirection, test.code.IsRedirection())
+		equals(name+" IsClientError", t, test.expectedClientError, test.code.IsClientError())
+		equals(name+" IsServerError", t, test.expectedServerError, test.code.IsServerError())
+	}
+}
+
+func TestStatusCodeReasonPhrase(t *testing.T) {
+	equals("200", t, "OK", StatusOk.ReasonPhrase())
+	equals("413", t, "Content Too Large", StatusContentTooLarge.ReasonPhrase())
+	equals("505", t, "HTTP Version Not Supported", StatusHttpVersionNotSupported.ReasonPhrase())
+	equals("299", t, "", StatusCode(299).ReasonPhrase())
+}
+
+func TestHttp11ResponseStatus(t *testing.T) {
+	tests := []struct {
+		statusCode  []byte
+		expected    StatusCode
+		expectedErr error
+	}{
+		{statusCode: []byte("200"), expected: StatusOk},
+		{statusCode: []byte("599"), expected: 599},
+		{statusCode: []byte("099"), expectedErr: ErrInvalidStatusLine},
+		{statusCode: []byte("20"), expectedErr: ErrInvalidStatusLine},
+		{statusCode: []byte("+20"), expectedErr: ErrInvalidStatusLine},
+	}
+
+	for _, test := range tests {
+		resp := Http11Response{StatusCode: test.statusCode}
+		code, err := resp.Status()
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("%s: expected: %v, actual: %v", test.statusCode, test.expectedErr, err)
+			continue
+		}
+		equals(string(test.statusCode), t, test.expected, code)
+	}
+}
@@ -0,0 +1,193 @@
+package http11p
+
+import (
+	"bytes"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC9112 - 6.1. Transfer-Encoding
+//
+// A sender MUST NOT apply the chunked transfer coding more than once to a
+// message body (i.e., chunking an already chunked message is not allowed).
+// If any transfer coding other than chunked is applied to a request's
+// content, the sender MUST apply chunked as the final transfer coding to
+// ensure that the message is properly framed.
+//
+// A sender MUST NOT send a Content-Length header field in any message that
+// contains a Transfer-Encoding header field.
+//
+// RFC9112 - 6.3. Message Body Length
+//
+// If a message is received with both a Transfer-Encoding and a
+// Content-Length header field, the Transfer-Encoding overrides the
+// Content-Length. Such a message might indicate an attempt to perform
+// request smuggling (Section 11.2) or response splitting (Section 11.1) and
+// ought to be handled as an error.
+//
+
+// Validate reports the violations of the message framing rules in req,
+// which are used for request smuggling. Each violation wraps one of
+// ErrWhitespaceBeforeColon, ErrInvalidContentLength,
+// ErrConflictingContentLength, ErrContentLengthWithTransferEncoding,
+// ErrChunkedNotFinal, ErrObfuscatedTransferEncoding and
+// ErrTransferEncodingInHttp10. It returns an empty slice if there is no
+// violation.
+//
+// NOTE
+// Marshal and the other parsers reject most of these violations, but accept
+// a request with both Content-Length and Transfer-Encoding as RFC9112
+// allows. Validate also checks requests which are built or modified by the
+// caller.
+func (req Http11Request) Validate() (violations []error) {
+	violations = []error{}
+	violations = append(violations, validateFieldNames(req.FieldLines)...)
+	violations = append(violations, validateFraming(req.FieldLines)...)
+	err := checkTransferEncodingVersion(req.HttpVersion, req.FieldLines)
+	if err != nil {
+		violations = append(violations, err)
+	}
+	return
+}
+
+func validateFieldNames(fieldLines FieldSection) (violations []error) {
+	for _, fieldLine := range fieldLines {
+		fieldName := fieldLine.FieldName
+		if len(fieldName) > 0 && (fieldName[len(fieldName)-1] == ' ' || fieldName[len(fieldName)-1] == '\t') {
+			violations = append(violations, newParseError(
+				ErrWhitespaceBeforeColon,
+				"field-name",
+				"whitespace between field-name and colon",
+				nil,
+			))
+		}
+	}
+	return
+}
+
+func validateFraming(fieldLines FieldSection) (violations []error) {
+	_, _, err := getContentLength(fieldLines)
+	if err != nil {
+		violations = append(violations, err)
+	}
+
+	if !fieldLines.Has("Transfer-Encoding") {
+		return
+	}
+	if fieldLines.Has("Content-Length") {
+		violations = append(violations, newParseError(
+			ErrContentLengthWithTransferEncoding,
+			"Transfer-Encoding",
+			"Content-Length with Transfer-Encoding",
+			nil,
+		))
+	}
+
+	transferCodings := fieldLines.ListMembers("Transfer-Encoding")
+	obfuscated := false
+	chunkedNotFinal := len(transferCodings) == 0
+	for i, transferCoding := range transferCodings {
+		name, remaining := abnfp.Parse(transferCoding, NewTransferCodingFinder())
+		name, _ = abnfp.Parse(name, NewTokenFinder())
+		isLast := i == len(transferCodings)-1
+		if len(remaining) != 0 || len(name) == 0 || isObfuscatedChunked(name) {
+			obfuscated = true
+			chunkedNotFinal = chunkedNotFinal || isLast
+			continue
+		}
+		if bytes.EqualFold(name, []byte("chunked")) != isLast {
+			chunkedNotFinal = true
+		}
+	}
+	if obfuscated {
+		violations = append(violations, newParseError(
+			ErrObfuscatedTransferEncoding,
+			"Transfer-Encoding",
+			"obfuscated Transfer-Encoding",
+			nil,
+		))
+	}
+	if chunkedNotFinal {
+		violations = append(violations, newParseError(
+			ErrChunkedNotFinal,
+			"Transfer-Encoding",
+			"chunked is not the final transfer coding",
+			nil,
+		))
+	}
+	return
+}
+
+// isObfuscatedChunked reports whether a transfer-coding name looks like
+// chunked, e.g. "xchunked" or "chunked-false", which some recipients may
+// treat as chunked and others may not.
+func isObfuscatedChunked(name []byte) bool {
+	lower := bytes.ToLower(name)
+	return bytes.Contains(lower, []byte("chunked")) && !bytes.Equal(lower, []byte("chunked"))
+}
+
+// RFC9112 - 3.2. Request Target
+//
+// A client MUST send a Host header field (Section 7.2 of [HTTP]) in all
+// HTTP/1.1 request messages. If the target URI includes an authority
+// component, then a client MUST send a field value for Host that is
+// identical to that authority component, excluding any userinfo
+// subcomponent and its "@" delimiter (Section 4.2 of [HTTP]). If the
+// authority component is missing or undefined for the target URI, then a
+// client MUST send a Host header field with an empty field value.
+//
+// A server MUST respond with a 400 (Bad Request) status code to any HTTP/1.1
+// request message that lacks a Host header field and to any request message
+// that contains more than one Host header field line or a Host header field
+// with an invalid field value.
+//
+
+// ValidateHost reports the violations of the rules for the Host field in
+// req. Each violation wraps one of ErrMissingHost, ErrDuplicateHost,
+// ErrInvalidHost and ErrHostMismatch. It returns an empty slice if there is
+// no violation.
+//
+// NOTE
+// ErrHostMismatch is reported when the Host field differs from the authority
+// of an absolute-form request-target. A proxy ignores such a Host field and
+// replaces it with the authority, so the caller may choose to tolerate it.
+func (req Http11Request) ValidateHost() (violations []error) {
+	violations = []error{}
+	hosts := req.FieldLines.Values("Host")
+	switch {
+	case len(hosts) == 0:
+		version, err := req.Version()
+		if err != nil || version.RequiresHost() {
+			violations = append(violations, newParseError(ErrMissingHost, "Host", "Host not found", nil))
+		}
+		return
+	case len(hosts) > 1:
+		violations = append(violations, newParseError(ErrDuplicateHost, "Host", "more than one Host", nil))
+		return
+	}
+
+	host := hosts[0]
+	if !matchesAll(host, NewHostFinder()) {
+		violations = append(violations, newParseError(ErrInvalidHost, "Host", "invalid Host", nil))
+		return
+	}
+
+	target, err := req.ParseRequestTarget()
+	if err != nil || target.Form != AbsoluteForm {
+		return
+	}
+	authority := append([]byte{}, target.Host...)
+	if target.Port != nil {
+		authority = append(authority, ':')
+		authority = append(authority, target.Port...)
+	}
+	if !bytes.EqualFold(host, authority) {
+		violations = append(violations, newParseError(
+			ErrHostMismatch,
+			"Host",
+			"Host differs from authority of request-target",
+			nil,
+		))
+	}
+	return
+}
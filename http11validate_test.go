@@ -0,0 +1,305 @@
+package http11p
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestHttp11RequestValidate(t *testing.T) {
+	tests := []struct {
+		testName   string
+		fieldLines FieldSection
+		expected   []error
+	}{
+		{
+			testName: "Content-Length",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("3, 3")},
+			},
+			expected: []error{},
+		},
+		{
+			testName: "Transfer-Encoding: gzip, chunked",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("gzip")},
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("Chunked")},
+			},
+			expected: []error{},
+		},
+		{
+			testName: "Content-Length and Transfer-Encoding",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("3")},
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked")},
+			},
+			expected: []error{ErrContentLengthWithTransferEncoding},
+		},
+		{
+			testName: "differing Content-Length",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("3")},
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("4")},
+			},
+			expected: []error{ErrConflictingContentLength},
+		},
+		{
+			testName: "invalid Content-Length",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("+3")},
+			},
+			expected: []error{ErrInvalidContentLength},
+		},
+		{
+			testName: "chunked is not final",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked, gzip")},
+			},
+			expected: []error{ErrChunkedNotFinal},
+		},
+		{
+			testName: "chunked twice",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked, chunked")},
+			},
+			expected: []error{ErrChunkedNotFinal},
+		},
+		{
+			testName: "whitespace before colon",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Transfer-Encoding "), FieldValue: []byte("chunked")},
+			},
+			expected: []error{ErrWhitespaceBeforeColon},
+		},
+		{
+			testName: "Transfer-Encoding: xchunked",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("xchunked")},
+			},
+			expected: []error{ErrObfuscatedTransferEncoding, ErrChunkedNotFinal},
+		},
+		{
+			testName: "Transfer-Encoding: \"chunked\"",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("gzip, \"chunked\"")},
+			},
+			expected: []error{ErrObfuscatedTransferEncoding, ErrChunkedNotFinal},
+		},
+		{
+			testName: "Transfer-Encoding: chunked;",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked;")},
+			},
+			expected: []error{ErrObfuscatedTransferEncoding, ErrChunkedNotFinal},
+		},
+		{
+			testName: "multiple violations",
+			fieldLines: FieldSection{
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("3")},
+				{FieldName: []byte("Content-Length"), FieldValue: []byte("4")},
+				{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked, identity")},
+			},
+			expected: []error{
+				ErrConflictingContentLength,
+				ErrContentLengthWithTransferEncoding,
+				ErrChunkedNotFinal,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			req := Http11Request{
+				Method:        []byte("POST"),
+				RequestTarget: []byte("/"),
+				HttpVersion:   []byte("HTTP/1.1"),
+				FieldLines:    test.fieldLines,
+			}
+			violations := req.Validate()
+			if len(violations) != len(test.expected) {
+				t.Errorf("expected: %v, actual: %v", test.expected, violations)
+				return
+			}
+			for i, expected := range test.expected {
+				if !errors.Is(violations[i], expected) {
+					t.Errorf("expected: %v, actual: %v", expected, violations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHttp11RequestValidateHost(t *testing.T) {
+	tests := []struct {
+		testName      string
+		requestTarget []byte
+		httpVersion   []byte
+		hosts         []string
+		expected      []error
+	}{
+		{
+			testName:      "Host",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"www.example.org:8080"},
+			expected:      []error{},
+		},
+		{
+			testName:      "no Host",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.1"),
+			expected:      []error{ErrMissingHost},
+		},
+		{
+			testName:      "no Host in HTTP/1.0",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.0"),
+			expected:      []error{},
+		},
+		{
+			testName:      "two Hosts",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"www.example.org", "www.example.org"},
+			expected:      []error{ErrDuplicateHost},
+		},
+		{
+			testName:      "two Hosts in HTTP/1.0",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.0"),
+			hosts:         []string{"a", "b"},
+			expected:      []error{ErrDuplicateHost},
+		},
+		{
+			testName:      "invalid Host",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"www.example.org, evil.example"},
+			expected:      []error{ErrInvalidHost},
+		},
+		{
+			testName:      "IPv6 Host",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"[2001:db8::1]:8080"},
+			expected:      []error{},
+		},
+		{
+			testName:      "invalid IPv6 Host",
+			requestTarget: []byte("/"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"[2001:db8::1::2]"},
+			expected:      []error{ErrInvalidHost},
+		},
+		{
+			testName:      "absolute-form with IPv6 address",
+			requestTarget: []byte("http://[2001:db8::1]:8080/a"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"[2001:DB8::1]:8080"},
+			expected:      []error{},
+		},
+		{
+			testName:      "absolute-form",
+			requestTarget: []byte("http://user@WWW.example.org:8080/a"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"www.example.org:8080"},
+			expected:      []error{},
+		},
+		{
+			testName:      "absolute-form with another Host",
+			requestTarget: []byte("http://www.example.org/a"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"evil.example"},
+			expected:      []error{ErrHostMismatch},
+		},
+		{
+			testName:      "absolute-form with another port",
+			requestTarget: []byte("http://www.example.org/a"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{"www.example.org:80"},
+			expected:      []error{ErrHostMismatch},
+		},
+		{
+			testName:      "absolute-form without authority",
+			requestTarget: []byte("urn:example:a"),
+			httpVersion:   []byte("HTTP/1.1"),
+			hosts:         []string{""},
+			expected:      []error{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			req := Http11Request{
+				Method:        []byte("GET"),
+				RequestTarget: test.requestTarget,
+				HttpVersion:   test.httpVersion,
+			}
+			for _, host := range test.hosts {
+				req.FieldLines.Add("Host", []byte(host))
+			}
+			violations := req.ValidateHost()
+			if len(violations) != len(test.expected) {
+				t.Errorf("expected: %v, actual: %v", test.expected, violations)
+				return
+			}
+			for i, expected := range test.expected {
+				if !errors.Is(violations[i], expected) {
+					t.Errorf("expected: %v, actual: %v", expected, violations[i])
+				}
+			}
+		})
+	}
+}
+
+// An empty Host is parsed and accepted, because field-value is
+// *field-content.
+func TestHttp11RequestValidateEmptyHost(t *testing.T) {
+	data := []byte("GET urn:example:a HTTP/1.1\r\nHost:\r\n\r\n")
+
+	reqs := map[string]*Http11Request{}
+	var req Http11Request
+	err := req.Marshal(data)
+	if err != nil {
+		t.Errorf("Marshal: %v", err)
+		return
+	}
+	reqs["Marshal"] = &req
+
+	p := NewRequestParser()
+	err = p.Feed(data)
+	if err != nil {
+		t.Errorf("RequestParser: %v", err)
+		return
+	}
+	reqs["RequestParser"] = p.Request()
+
+	readReq, err := ReadRequest(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Errorf("ReadRequest: %v", err)
+		return
+	}
+	reqs["ReadRequest"] = readReq
+
+	for name, actual := range reqs {
+		equals(name+" len(Host)", t, 1, len(actual.FieldLines.Values("Host")))
+		equals(name+" Host", t, "", string(actual.FieldLines.Get("Host")))
+		equals(name+" ValidateHost", t, 0, len(actual.ValidateHost()))
+	}
+}
+
+func TestHttp11RequestValidateHttp10(t *testing.T) {
+	req := Http11Request{
+		Method:        []byte("POST"),
+		RequestTarget: []byte("/"),
+		HttpVersion:   []byte("HTTP/1.0"),
+		FieldLines: FieldSection{
+			{FieldName: []byte("Transfer-Encoding"), FieldValue: []byte("chunked")},
+		},
+	}
+	violations := req.Validate()
+	if len(violations) != 1 || !errors.Is(violations[0], ErrTransferEncodingInHttp10) {
+		t.Errorf("expected: %v, actual: %v", []error{ErrTransferEncodingInHttp10}, violations)
+	}
+}
@@ -0,0 +1,135 @@
+package http11p
+
+import (
+	"fmt"
+)
+
+// RFC9110 - 2.5. Protocol Version
+//
+// HTTP's version number consists of two decimal digits separated by a "."
+// (period or decimal point). The first digit (major version) indicates the
+// messaging syntax, whereas the second digit (minor version) indicates the
+// highest minor version within that major version to which the sender is
+// conformant (able to understand for future communication).
+//
+// A recipient that receives a message with a major version number that it
+// implements and a minor version number higher than what it implements
+// SHOULD process the message as if it were in the highest minor version
+// within that major version to which the recipient is conformant.
+//
+// RFC9110 - 15.6.6. 505 HTTP Version Not Supported
+//
+// The 505 (HTTP Version Not Supported) status code indicates that the server
+// does not support, or refuses to support, the major version of HTTP that
+// was used in the request message.
+//
+
+// HttpVersion is HTTP-version split into its major and minor versions.
+type HttpVersion struct {
+	Major int
+	Minor int
+}
+
+var (
+	Http10 = HttpVersion{Major: 1, Minor: 0}
+	Http11 = HttpVersion{Major: 1, Minor: 1}
+)
+
+// isHttpVersionPrefix reports whether data is the part of HTTP-version
+// received so far, e.g. "HTTP/1.".
+func isHttpVersionPrefix(data []byte) bool {
+	const pattern = "HTTP/0.0"
+	if len(data) > len(pattern) {
+		return false
+	}
+	for i, b := range data {
+		if pattern[i] == '0' {
+			if b < '0' || b > '9' {
+				return false
+			}
+		} else if b != pattern[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseHttpVersion converts HTTP-version to HttpVersion. If data is not
+// HTTP-version, the error is a *ParseError wrapping ErrInvalidHttpVersion.
+// It does not check whether the version is supported.
+func ParseHttpVersion(data []byte) (version HttpVersion, err error) {
+	if !matchesAll(data, NewHttpVersionFinder()) {
+		return version, newParseError(ErrInvalidHttpVersion, "HTTP-version", "invalid http-version", nil)
+	}
+	// HTTP-version = HTTP-name "/" DIGIT "." DIGIT
+	version.Major = int(data[5] - '0')
+	version.Minor = int(data[7] - '0')
+	return version, nil
+}
+
+func (version HttpVersion) String() string {
+	return fmt.Sprintf("HTTP/%d.%d", version.Major, version.Minor)
+}
+
+// Bytes returns version as HTTP-version.
+func (version HttpVersion) Bytes() []byte {
+	return []byte(version.String())
+}
+
+// AtLeast reports whether version is other or a later version.
+func (version HttpVersion) AtLeast(other HttpVersion) bool {
+	if version.Major != other.Major {
+		return version.Major > other.Major
+	}
+	return version.Minor >= other.Minor
+}
+
+// IsSupported reports whether the major version of version is 1, which this
+// package implements. HTTP/1.x with x > 1 is processed as HTTP/1.1.
+func (version HttpVersion) IsSupported() bool {
+	return version.Major == 1
+}
+
+// IsPersistentByDefault reports whether a connection is persistent unless
+// the "close" connection option is sent, which is true since HTTP/1.1.
+// HTTP/1.0 connections are closed unless the "keep-alive" connection option
+// is sent.
+func (version HttpVersion) IsPersistentByDefault() bool {
+	return version.AtLeast(Http11)
+}
+
+// RequiresHost reports whether a request must contain the Host field, which
+// is true since HTTP/1.1.
+func (version HttpVersion) RequiresHost() bool {
+	return version.AtLeast(Http11)
+}
+
+// SupportsTransferEncoding reports whether a message can be sent with
+// Transfer-Encoding, including the chunked transfer coding, which is true
+// since HTTP/1.1.
+func (version HttpVersion) SupportsTransferEncoding() bool {
+	return version.AtLeast(Http11)
+}
+
+// Version returns req.HttpVersion as HttpVersion.
+func (req Http11Request) Version() (HttpVersion, error) {
+	return ParseHttpVersion(req.HttpVersion)
+}
+
+// Version returns resp.HttpVersion as HttpVersion.
+func (resp Http11Response) Version() (HttpVersion, error) {
+	return ParseHttpVersion(resp.HttpVersion)
+}
+
+// checkHttpVersion returns an error if the HTTP-version at the start of
+// data is not supported.
+func checkHttpVersion(httpVersion []byte, data []byte) error {
+	version, err := ParseHttpVersion(httpVersion)
+	if err != nil {
+		return withRest(err, data)
+	}
+	if !version.IsSupported() {
+		return newParseError(ErrUnsupportedHttpVersion, "HTTP-version", "unsupported http-version", data)
+	}
+	return nil
+}
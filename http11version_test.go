@@ -0,0 +1,89 @@
+package http11p
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseHttpVersion(t *testing.T) {
+	tests := []struct {
+		data        []byte
+		expected    HttpVersion
+		expectedErr error
+	}{
+		{data: []byte("HTTP/1.1"), expected: Http11},
+		{data: []byte("HTTP/1.0"), expected: Http10},
+		{data: []byte("HTTP/2.0"), expected: HttpVersion{Major: 2, Minor: 0}},
+		{data: []byte("HTTP/1.10"), expectedErr: ErrInvalidHttpVersion},
+		{data: []byte("http/1.1"), expectedErr: ErrInvalidHttpVersion},
+		{data: []byte{}, expectedErr: ErrInvalidHttpVersion},
+	}
+
+	for _, test := range tests {
+		version, err := ParseHttpVersion(test.data)
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("%q: expected: %v, actual: %v", test.data, test.expectedErr, err)
+			continue
+		}
+		equals(string(test.data), t, test.expected, version)
+	}
+}
+
+func TestHttpVersionSemantics(t *testing.T) {
+	tests := []struct {
+		version                          HttpVersion
+		expectedString                   string
+		expectedSupported                bool
+		expectedPersistentByDefault      bool
+		expectedRequiresHost             bool
+		expectedSupportsTransferEncoding bool
+	}{
+		{
+			version:        HttpVersion{Major: 0, Minor: 9},
+			expectedString: "HTTP/0.9",
+		},
+		{
+			version:           Http10,
+			expectedString:    "HTTP/1.0",
+			expectedSupported: true,
+		},
+		{
+			version:                          Http11,
+			expectedString:                   "HTTP/1.1",
+			expectedSupported:                true,
+			expectedPersistentByDefault:      true,
+			expectedRequiresHost:             true,
+			expectedSupportsTransferEncoding: true,
+		},
+		{
+			version:                          HttpVersion{Major: 1, Minor: 2},
+			expectedString:                   "HTTP/1.2",
+			expectedSupported:                true,
+			expectedPersistentByDefault:      true,
+			expectedRequiresHost:             true,
+			expectedSupportsTransferEncoding: true,
+		},
+		{
+			version:                          HttpVersion{Major: 2, Minor: 0},
+			expectedString:                   "HTTP/2.0",
+			expectedPersistentByDefault:      true,
+			expectedRequiresHost:             true,
+			expectedSupportsTransferEncoding: true,
+		},
+	}
+
+	for _, test := range tests {
+		name := test.expectedString
+		equals(name+" String", t, test.expectedString, test.version.String())
+		equals(name+" IsSupported", t, test.expectedSupported, test.version.IsSupported())
+		equals(name+" IsPersistentByDefault", t, test.expectedPersistentByDefault, test.version.IsPersistentByDefault())
+		equals(name+" RequiresHost", t, test.expectedRequiresHost, test.version.RequiresHost())
+		equals(name+" SupportsTransferEncoding", t, test.expectedSupportsTransferEncoding, test.version.SupportsTransferEncoding())
+	}
+}
+
+func TestUnsupportedHttpVersionStatusCode(t *testing.T) {
+	var req Http11Request
+	err := req.Marshal([]byte("GET / HTTP/2.0\r\n\r\n"))
+	equals("StatusCodeForError", t, 505, StatusCodeForError(err))
+}
@@ -0,0 +1,101 @@
+package http11p
+
+import (
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC9110 - 7.6.3. Via
+//
+// The "Via" header field indicates the presence of intermediate protocols
+// and recipients between the user agent and the server (on requests) or
+// between the origin server and the client (on responses), similar to the
+// "Received" header field in email.
+//
+// Multiple Via field values represent each proxy or gateway that has
+// forwarded the message. Each intermediary appends its own information
+// about how the message was received, such that the end result is ordered
+// according to the sequence of forwarding recipients.
+//
+// The received-protocol indicates the protocol version of the message
+// received by the server or client along each segment of the request/
+// response chain. The received-protocol version is appended to the Via field
+// value when the message is forwarded so that information about the protocol
+// capabilities of upstream applications remains visible to all recipients.
+//
+// The protocol-name is excluded if and only if it would be "HTTP".
+//
+
+// Via is an element of the Via field.
+type Via struct {
+	// ProtocolName is empty if the sender omitted the protocol-name, which
+	// means HTTP.
+	ProtocolName    []byte
+	ProtocolVersion []byte
+	ReceivedBy      []byte
+
+	// Comment includes the parentheses, e.g. "(Apache/1.1)". It is empty if
+	// there is no comment.
+	Comment []byte
+}
+
+// Via returns the elements of the Via field in order. If the Via field is
+// invalid, the error is a *ParseError wrapping ErrInvalidVia.
+func (fieldSection FieldSection) Via() (vias []Via, err error) {
+	vias = []Via{}
+	elements, ok := fieldSection.listElements("Via", NewViaElementFinder())
+	if !ok {
+		return vias, newParseError(ErrInvalidVia, "Via", "invalid Via", nil)
+	}
+	for _, element := range elements {
+		vias = append(vias, marshalVia(element))
+	}
+	return vias, nil
+}
+
+// marshalVia splits via-element, which is checked by NewViaElementFinder.
+func marshalVia(data []byte) (via Via) {
+	receivedProtocol, remaining := abnfp.Parse(data, NewReceivedProtocolFinder())
+	protocolName, rest := abnfp.Parse(receivedProtocol, NewProtocolNameFinder())
+	if len(rest) > 0 {
+		// Skip "/".
+		via.ProtocolName = protocolName
+		via.ProtocolVersion = rest[1:]
+	} else {
+		via.ProtocolVersion = protocolName
+	}
+
+	_, remaining = abnfp.Parse(remaining, NewRwsFinder())
+	via.ReceivedBy, remaining = abnfp.Parse(remaining, NewReceivedByFinder())
+
+	_, remaining = abnfp.Parse(remaining, NewRwsFinder())
+	via.Comment = remaining
+	return
+}
+
+// Unmarshal returns via as via-element.
+func (via Via) Unmarshal() (data []byte) {
+	if len(via.ProtocolName) > 0 {
+		data = append(data, via.ProtocolName...)
+		data = append(data, '/')
+	}
+	data = append(data, via.ProtocolVersion...)
+	data = append(data, ' ')
+	data = append(data, via.ReceivedBy...)
+	if len(via.Comment) > 0 {
+		data = append(data, ' ')
+		data = append(data, via.Comment...)
+	}
+	return
+}
+
+// AddVia appends via to the Via field. If via is not a valid via-element,
+// the error is a *ParseError wrapping ErrInvalidVia and fieldSection is not
+// changed.
+func (fieldSection *FieldSection) AddVia(via Via) error {
+	data := via.Unmarshal()
+	if !matchesAll(data, NewViaElementFinder()) {
+		return newParseError(ErrInvalidVia, "Via", "invalid Via", nil)
+	}
+	fieldSection.addListMember("Via", data)
+	return nil
+}
@@ -0,0 +1,111 @@
+package http11p
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFieldSectionVia(t *testing.T) {
+	tests := []struct {
+		testName    string
+		fieldValues []string
+		expectedErr error
+		expected    []Via
+	}{
+		{
+			testName:    "no Via",
+			fieldValues: []string{},
+			expected:    []Via{},
+		},
+		{
+			testName:    "elements in multiple lines",
+			fieldValues: []string{"1.0 fred, 1.1 p.example.net", "HTTP/2 [::1]:8080 (Apache/1.1, mod_proxy)"},
+			expected: []Via{
+				{ProtocolVersion: []byte("1.0"), ReceivedBy: []byte("fred")},
+				{ProtocolVersion: []byte("1.1"), ReceivedBy: []byte("p.example.net")},
+				{ProtocolName: []byte("HTTP"), ProtocolVersion: []byte("2"), ReceivedBy: []byte("[::1]:8080"), Comment: []byte("(Apache/1.1, mod_proxy)")},
+			},
+		},
+		{
+			testName:    "empty list elements",
+			fieldValues: []string{", 1.1 fred ,"},
+			expected: []Via{
+				{ProtocolVersion: []byte("1.1"), ReceivedBy: []byte("fred")},
+			},
+		},
+		{
+			testName:    "no received-by",
+			fieldValues: []string{"1.1"},
+			expectedErr: ErrInvalidVia,
+		},
+		{
+			testName:    "unbalanced comment",
+			fieldValues: []string{"1.1 fred (a"},
+			expectedErr: ErrInvalidVia,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			fieldSection := FieldSection{}
+			for _, fieldValue := range test.fieldValues {
+				fieldSection.Add("Via", []byte(fieldValue))
+			}
+			vias, err := fieldSection.Via()
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("%v: expected: %v, actual: %v", test.testName, test.expectedErr, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+			equals(test.testName+" len(vias)", t, len(test.expected), len(vias))
+			for i := 0; i < len(test.expected) && i < len(vias); i++ {
+				equals(test.testName+" ProtocolName", t, string(test.expected[i].ProtocolName), string(vias[i].ProtocolName))
+				equals(test.testName+" ProtocolVersion", t, string(test.expected[i].ProtocolVersion), string(vias[i].ProtocolVersion))
+				equals(test.testName+" ReceivedBy", t, string(test.expected[i].ReceivedBy), string(vias[i].ReceivedBy))
+				equals(test.testName+" Comment", t, string(test.expected[i].Comment), string(vias[i].Comment))
+			}
+		})
+	}
+}
+
+func TestViaUnmarshal(t *testing.T) {
+	tests := []struct {
+		testName string
+		via      Via
+		expected string
+	}{
+		{
+			testName: "HTTP",
+			via:      Via{ProtocolVersion: []byte("1.1"), ReceivedBy: []byte("fred")},
+			expected: "1.1 fred",
+		},
+		{
+			testName: "protocol-name and comment",
+			via:      Via{ProtocolName: []byte("FSTR"), ProtocolVersion: []byte("1"), ReceivedBy: []byte("a:80"), Comment: []byte("(b)")},
+			expected: "FSTR/1 a:80 (b)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			equals(test.testName, t, test.expected, string(test.via.Unmarshal()))
+		})
+	}
+}
+
+func TestFieldSectionAddVia(t *testing.T) {
+	fieldSection := FieldSection{
+		{FieldName: []byte("Via"), FieldValue: []byte("1.0 fred")},
+		{FieldName: []byte("Content-Length"), FieldValue: []byte("0")},
+	}
+	err := fieldSection.AddVia(Via{ProtocolVersion: []byte("1.1"), ReceivedBy: []byte("p.example.net")})
+	equals("err", t, true, err == nil)
+	equals("len(fieldSection)", t, 2, len(fieldSection))
+	equals("Via", t, "1.0 fred, 1.1 p.example.net", string(fieldSection.Get("Via")))
+
+	err = fieldSection.AddVia(Via{ProtocolVersion: []byte("1.1"), ReceivedBy: []byte("a b")})
+	equals("ErrInvalidVia", t, true, errors.Is(err, ErrInvalidVia))
+	equals("Via", t, "1.0 fred, 1.1 p.example.net", string(fieldSection.Get("Via")))
+}